@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// FrameScoreDiff compares the motion score of one frame in two analyses
+type FrameScoreDiff struct {
+	Frame  int     `json:"frame"`
+	ScoreA float64 `json:"score_a"`
+	ScoreB float64 `json:"score_b"`
+	Diff   float64 `json:"diff"` // ScoreB - ScoreA
+}
+
+// AnalysisComparison is the result of CompareAnalysisRecords
+type AnalysisComparison struct {
+	RecordA         AnalysisResult   `json:"record_a"`
+	RecordB         AnalysisResult   `json:"record_b"`
+	ScoreDiff       float64          `json:"score_diff"`        // mean score of B minus mean score of A
+	FrameScoreDiffs []FrameScoreDiff `json:"frame_score_diffs"` // frames present in both, in frame order
+	Summary         string           `json:"summary"`
+}
+
+// CompareAnalysisRecords compares two analyses of the same video, such as runs
+// with different configurations, which the backend keeps in one database file.
+// Frames are matched by index; frames only one analysis covers are left out.
+func (a *App) CompareAnalysisRecords(databasePath string, idA string, idB string) (AnalysisComparison, error) {
+	recordA, err := a.findAnalysisRecord(databasePath, idA)
+	if err != nil {
+		return AnalysisComparison{}, err
+	}
+	recordB, err := a.findAnalysisRecord(databasePath, idB)
+	if err != nil {
+		return AnalysisComparison{}, err
+	}
+
+	comparison := AnalysisComparison{
+		RecordA:         summarizeAnalysis(recordA),
+		RecordB:         summarizeAnalysis(recordB),
+		FrameScoreDiffs: []FrameScoreDiff{},
+	}
+	comparison.ScoreDiff = comparison.RecordB.MeanScore - comparison.RecordA.MeanScore
+
+	scoresA := make(map[int]float64, len(recordA.FrameData))
+	for _, frame := range recordA.FrameData {
+		scoresA[frame.FrameIndex] = frame.MotionIntensityScore
+	}
+	for _, frame := range recordB.FrameData {
+		scoreA, ok := scoresA[frame.FrameIndex]
+		if !ok {
+			continue
+		}
+		comparison.FrameScoreDiffs = append(comparison.FrameScoreDiffs, FrameScoreDiff{
+			Frame:  frame.FrameIndex,
+			ScoreA: scoreA,
+			ScoreB: frame.MotionIntensityScore,
+			Diff:   frame.MotionIntensityScore - scoreA,
+		})
+	}
+
+	comparison.Summary = comparisonSummary(comparison.RecordA.MeanScore, comparison.RecordB.MeanScore)
+	return comparison, nil
+}
+
+// comparisonSummary describes how the mean score of B differs from A, e.g.
+// "Config B scored 12% higher on average"
+func comparisonSummary(meanA, meanB float64) string {
+	diff := meanB - meanA
+	if math.Abs(diff) < 1e-9 {
+		return "Both configs scored the same on average"
+	}
+	direction := "higher"
+	if diff < 0 {
+		direction = "lower"
+	}
+	if meanA == 0 {
+		return fmt.Sprintf("Config B scored %.3f %s on average", math.Abs(diff), direction)
+	}
+	return fmt.Sprintf("Config B scored %.0f%% %s on average", math.Abs(diff)/math.Abs(meanA)*100, direction)
+}
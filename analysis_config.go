@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// MotionWeights are the weights of each motion component in the motion intensity score
+type MotionWeights struct {
+	Displacement    float64 `json:"displacement"`
+	Velocity        float64 `json:"velocity"`
+	Acceleration    float64 `json:"acceleration"`
+	DirectionChange float64 `json:"direction_change"`
+	PoseChange      float64 `json:"pose_change"`
+}
+
+// AnalysisConfig holds the analysis parameters understood by the backend
+type AnalysisConfig struct {
+	ThresholdHigh    float64       `json:"threshold_high"`
+	ThresholdLow     float64       `json:"threshold_low"`
+	HysteresisMargin float64       `json:"hysteresis_margin"`
+	MinDuration      float64       `json:"min_duration"` // seconds
+	SmoothingMethod  string        `json:"smoothing_method"`
+	SmoothingAlpha   float64       `json:"smoothing_alpha"`
+	MotionWeights    MotionWeights `json:"motion_weights"`
+	EnableTameTsume  bool          `json:"enable_tame_tsume"`
+	SaveKeypoints    bool          `json:"save_keypoints"`
+}
+
+// smoothingMethods are the smoothing methods supported by the backend
+var smoothingMethods = []string{"ema", "window"}
+
+// DefaultAnalysisConfig returns the backend's default analysis parameters
+func DefaultAnalysisConfig() AnalysisConfig {
+	return AnalysisConfig{
+		ThresholdHigh:    0.60,
+		ThresholdLow:     0.35,
+		HysteresisMargin: 0.05,
+		MinDuration:      0.08,
+		SmoothingMethod:  "ema",
+		SmoothingAlpha:   0.7,
+		MotionWeights: MotionWeights{
+			Displacement:    0.2,
+			Velocity:        0.25,
+			Acceleration:    0.2,
+			DirectionChange: 0.15,
+			PoseChange:      0.2,
+		},
+	}
+}
+
+// ParseAnalysisConfig reads a legacy JSON string config. Keys that are
+// missing keep their default values.
+func ParseAnalysisConfig(raw string) (AnalysisConfig, error) {
+	config := DefaultAnalysisConfig()
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		return AnalysisConfig{}, err
+	}
+	return config, nil
+}
+
+// Validate checks that every parameter is within the range the backend accepts
+func (c AnalysisConfig) Validate() error {
+	if c.ThresholdLow < 0 || c.ThresholdHigh > 1 {
+		return fmt.Errorf("thresholds must be between 0 and 1")
+	}
+	if c.ThresholdHigh <= c.ThresholdLow {
+		return fmt.Errorf("high threshold (%g) must be greater than low threshold (%g)", c.ThresholdHigh, c.ThresholdLow)
+	}
+	if c.HysteresisMargin < 0 || c.HysteresisMargin > c.ThresholdHigh-c.ThresholdLow {
+		return fmt.Errorf("hysteresis margin must be between 0 and the threshold range (%g)", c.ThresholdHigh-c.ThresholdLow)
+	}
+	if c.MinDuration < 0 {
+		return fmt.Errorf("minimum duration must not be negative")
+	}
+	if !containsString(smoothingMethods, c.SmoothingMethod) {
+		return fmt.Errorf("unknown smoothing method %q", c.SmoothingMethod)
+	}
+	if c.SmoothingAlpha <= 0 || c.SmoothingAlpha > 1 {
+		return fmt.Errorf("smoothing alpha must be greater than 0 and at most 1")
+	}
+
+	weights := []float64{
+		c.MotionWeights.Displacement,
+		c.MotionWeights.Velocity,
+		c.MotionWeights.Acceleration,
+		c.MotionWeights.DirectionChange,
+		c.MotionWeights.PoseChange,
+	}
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 || math.IsNaN(weight) {
+			return fmt.Errorf("motion weights must not be negative")
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("at least one motion weight must be positive")
+	}
+
+	return nil
+}
+
+// Marshal returns the JSON string passed to the backend
+func (c AnalysisConfig) Marshal() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDefaultAnalysisConfig returns the default analysis parameters for the UI
+func (a *App) GetDefaultAnalysisConfig() AnalysisConfig {
+	return DefaultAnalysisConfig()
+}
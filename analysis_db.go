@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AnalysisKeypoint is a pose landmark stored with a frame, in pixels
+type AnalysisKeypoint struct {
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	Name string `json:"name"`
+}
+
+// AnalysisFrame is the stored analysis of one video frame
+type AnalysisFrame struct {
+	FrameIndex           int                `json:"frame_index"`
+	Timestamp            float64            `json:"timestamp"` // seconds
+	MotionIntensityScore float64            `json:"motion_intensity_score"`
+	MotionState          string             `json:"motion_state"` // LOW, MID or HIGH
+	Keypoints            []AnalysisKeypoint `json:"keypoints"`    // only when save_keypoints was set
+}
+
+// AnalysisRecord is an analysis saved by the backend
+type AnalysisRecord struct {
+	JobID             string                 `json:"job_id"`
+	DatabaseID        string                 `json:"database_id"`
+	DatabasePath      string                 `json:"database_path"`
+	SourceVideoPath   string                 `json:"source_video_path"`
+	OutputVideoPath   string                 `json:"output_video_path"`
+	AnalysisTimestamp string                 `json:"analysis_timestamp"`
+	Parameters        map[string]interface{} `json:"parameters"`
+	FrameData         []AnalysisFrame        `json:"frame_data"`
+}
+
+// defaultFrameDuration is assumed when the frame rate of an analysis cannot
+// be derived from its timestamps
+const defaultFrameDuration = 1.0 / 30
+
+// motionStateHolds is how many frames each drawing is held for per motion
+// state, matching MotionState.frame_hold_count in the backend
+var motionStateHolds = map[string]int{"LOW": 1, "MID": 3, "HIGH": 2}
+
+// analysisFrameDuration returns the interval between analysed frames in seconds
+func analysisFrameDuration(frames []AnalysisFrame) float64 {
+	if len(frames) > 1 {
+		if d := (frames[len(frames)-1].Timestamp - frames[0].Timestamp) / float64(len(frames)-1); d > 0 {
+			return d
+		}
+	}
+	return defaultFrameDuration
+}
+
+// analysisDBPath returns the database the backend writes next to an input
+// video: "<name>_analysis.json" in the same folder, as an absolute path
+func analysisDBPath(inputPath string) string {
+	if absPath, err := filepath.Abs(inputPath); err == nil {
+		inputPath = absPath
+	}
+	name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	return filepath.Join(filepath.Dir(inputPath), name+"_analysis.json")
+}
+
+// readAnalysisRecord loads one record from a backend database file, which is
+// a TinyDB JSON document keyed by table and record ID
+func readAnalysisRecord(dbPath, databaseID string) (AnalysisRecord, error) {
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		return AnalysisRecord{}, fmt.Errorf("failed to read analysis database %s: %v", dbPath, err)
+	}
+	var tables map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return AnalysisRecord{}, fmt.Errorf("failed to parse analysis database %s: %v", dbPath, err)
+	}
+	raw, ok := tables["_default"][databaseID]
+	if !ok {
+		return AnalysisRecord{}, fmt.Errorf("analysis %s not found in %s", databaseID, dbPath)
+	}
+
+	var record AnalysisRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return AnalysisRecord{}, fmt.Errorf("failed to parse analysis %s in %s: %v", databaseID, dbPath, err)
+	}
+	record.DatabaseID = databaseID
+	record.DatabasePath = dbPath
+	return record, nil
+}
+
+// findAnalysisRecord loads the analysis reported by ProcessVideoResponse as
+// DatabasePath and DatabaseID. IDs are only unique within one database file,
+// and the backend keeps a file per input video, so both are required.
+func (a *App) findAnalysisRecord(databasePath, databaseID string) (AnalysisRecord, error) {
+	if databasePath == "" || databaseID == "" {
+		return AnalysisRecord{}, fmt.Errorf("a database path and a database ID are required")
+	}
+	absPath, err := filepath.Abs(databasePath)
+	if err != nil {
+		return AnalysisRecord{}, fmt.Errorf("invalid database path %s: %v", databasePath, err)
+	}
+	record, err := readAnalysisRecord(absPath, databaseID)
+	if err != nil {
+		return AnalysisRecord{}, err
+	}
+
+	// The backend stores the temporary path it wrote to before the output was
+	// moved into place; the history knows the job and where the output went
+	if store, err := a.historyStore(); err == nil {
+		if item, err := store.LatestSuccessForAnalysis(absPath, databaseID); err == nil {
+			record.JobID = item.JobID
+			record.OutputVideoPath = item.OutputPath
+			return record, nil
+		}
+	}
+	record.OutputVideoPath = finalOutputPath(record.OutputVideoPath)
+	return record, nil
+}
+
+// defaultDetectionLimit is the page size of QueryDetections when no limit is given
+const defaultDetectionLimit = 500
+
+// MotionStateSummary aggregates the frames of one motion state
+type MotionStateSummary struct {
+	Frames     int     `json:"frames"`
+	Seconds    float64 `json:"seconds"`
+	MeanScore  float64 `json:"mean_score"`
+	HoldFrames int     `json:"hold_frames"` // frames each drawing is held for
+	Segments   int     `json:"segments"`    // runs of consecutive frames in this state
+}
+
+// AnalysisResult is a stored analysis without its per-frame data, which is
+// read page by page with QueryDetections
+type AnalysisResult struct {
+	JobID             string                        `json:"job_id"`
+	DatabaseID        string                        `json:"database_id"`
+	DatabasePath      string                        `json:"database_path"`
+	SourceVideoPath   string                        `json:"source_video_path"`
+	OutputVideoPath   string                        `json:"output_video_path"`
+	AnalysisTimestamp string                        `json:"analysis_timestamp"`
+	Parameters        map[string]interface{}        `json:"parameters"`
+	FrameCount        int                           `json:"frame_count"`
+	DurationSeconds   float64                       `json:"duration_seconds"`
+	MeanScore         float64                       `json:"mean_score"`
+	MaxScore          float64                       `json:"max_score"`
+	HasKeypoints      bool                          `json:"has_keypoints"`
+	States            map[string]MotionStateSummary `json:"states"` // by motion state
+}
+
+// DetectionFilter narrows QueryDetections results. Zero values match everything.
+type DetectionFilter struct {
+	// MotionStates keeps frames in any of the listed states
+	MotionStates []string `json:"motion_states,omitempty"`
+	MinScore     *float64 `json:"min_score,omitempty"`
+	MaxScore     *float64 `json:"max_score,omitempty"`
+	// Start and End bound the frame timestamps in seconds; End is exclusive
+	Start  *float64 `json:"start,omitempty"`
+	End    *float64 `json:"end,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+	Offset int      `json:"offset,omitempty"`
+}
+
+// matches reports whether a frame passes the filter
+func (f DetectionFilter) matches(frame AnalysisFrame) bool {
+	if len(f.MotionStates) > 0 {
+		found := false
+		for _, state := range f.MotionStates {
+			if strings.EqualFold(state, frame.MotionState) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinScore != nil && frame.MotionIntensityScore < *f.MinScore {
+		return false
+	}
+	if f.MaxScore != nil && frame.MotionIntensityScore > *f.MaxScore {
+		return false
+	}
+	if f.Start != nil && frame.Timestamp < *f.Start {
+		return false
+	}
+	if f.End != nil && frame.Timestamp >= *f.End {
+		return false
+	}
+	return true
+}
+
+// DetectionPage is one page of QueryDetections results
+type DetectionPage struct {
+	Total      int             `json:"total"` // matching frames across all pages
+	Detections []AnalysisFrame `json:"detections"`
+}
+
+// summarizeAnalysis computes the AnalysisResult of a record
+func summarizeAnalysis(record AnalysisRecord) AnalysisResult {
+	result := AnalysisResult{
+		JobID:             record.JobID,
+		DatabaseID:        record.DatabaseID,
+		DatabasePath:      record.DatabasePath,
+		SourceVideoPath:   record.SourceVideoPath,
+		OutputVideoPath:   record.OutputVideoPath,
+		AnalysisTimestamp: record.AnalysisTimestamp,
+		Parameters:        record.Parameters,
+		FrameCount:        len(record.FrameData),
+		States:            map[string]MotionStateSummary{},
+	}
+	if len(record.FrameData) == 0 {
+		return result
+	}
+
+	frameDuration := analysisFrameDuration(record.FrameData)
+	result.DurationSeconds = record.FrameData[len(record.FrameData)-1].Timestamp + frameDuration
+	var total float64
+	for i, frame := range record.FrameData {
+		total += frame.MotionIntensityScore
+		result.MaxScore = max(result.MaxScore, frame.MotionIntensityScore)
+		if len(frame.Keypoints) > 0 {
+			result.HasKeypoints = true
+		}
+
+		state, seen := result.States[frame.MotionState]
+		if !seen {
+			state.HoldFrames = max(motionStateHolds[frame.MotionState], 1)
+		}
+		if i == 0 || record.FrameData[i-1].MotionState != frame.MotionState {
+			state.Segments++
+		}
+		state.Frames++
+		state.Seconds += frameDuration
+		// Running sum; divided into a mean below
+		state.MeanScore += frame.MotionIntensityScore
+		result.States[frame.MotionState] = state
+	}
+	result.MeanScore = total / float64(len(record.FrameData))
+	for name, state := range result.States {
+		state.MeanScore /= float64(state.Frames)
+		result.States[name] = state
+	}
+	return result
+}
+
+// GetAnalysisResult returns the analysis with the given database ID in the
+// database file at databasePath, both as reported in ProcessVideoResponse,
+// summarized per motion state. Use QueryDetections to read its frames.
+func (a *App) GetAnalysisResult(databasePath string, databaseID string) (AnalysisResult, error) {
+	record, err := a.findAnalysisRecord(databasePath, databaseID)
+	if err != nil {
+		return AnalysisResult{}, err
+	}
+	return summarizeAnalysis(record), nil
+}
+
+// QueryDetections returns the analysed frames of a stored analysis that match
+// filter, in frame order
+func (a *App) QueryDetections(databasePath string, databaseID string, filter DetectionFilter) (DetectionPage, error) {
+	if filter.Offset < 0 {
+		return DetectionPage{}, fmt.Errorf("offset must not be negative")
+	}
+	record, err := a.findAnalysisRecord(databasePath, databaseID)
+	if err != nil {
+		return DetectionPage{}, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDetectionLimit
+	}
+	page := DetectionPage{Detections: []AnalysisFrame{}}
+	for _, frame := range record.FrameData {
+		if !filter.matches(frame) {
+			continue
+		}
+		if page.Total >= filter.Offset && len(page.Detections) < limit {
+			page.Detections = append(page.Detections, frame)
+		}
+		page.Total++
+	}
+	return page, nil
+}
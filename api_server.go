@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultAPIPort is the localhost port the HTTP API listens on by default
+	defaultAPIPort = 47821
+	// maxAPIRequestBody bounds the size of a POST /jobs body
+	maxAPIRequestBody = 1 << 20
+	// apiEventBuffer is how many events a slow SSE client may fall behind
+	// before further events are dropped
+	apiEventBuffer = 256
+	// apiHeartbeatInterval keeps idle SSE connections from being closed by proxies
+	apiHeartbeatInterval = 15 * time.Second
+)
+
+// APIServerSettings control the local HTTP API
+type APIServerSettings struct {
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port"`
+}
+
+// APIServerInfo describes the running HTTP API, for showing to the user
+type APIServerInfo struct {
+	Running   bool   `json:"running"`
+	URL       string `json:"url,omitempty"`
+	Token     string `json:"token,omitempty"`
+	TokenPath string `json:"token_path,omitempty"` // file scripts can read the token from
+}
+
+// apiServer is the running HTTP API
+type apiServer struct {
+	server *http.Server
+	url    string
+	token  string
+}
+
+// apiTokenPath returns the file holding the API token
+func apiTokenPath() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "api_token"), nil
+}
+
+// loadAPIToken returns the persisted API token, generating one on first use.
+// The file is only readable by the current user, who is who the API trusts.
+func loadAPIToken() (string, error) {
+	path, err := apiTokenPath()
+	if err != nil {
+		return "", err
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %v", err)
+	}
+	token := hex.EncodeToString(b)
+	if err := writeFileAtomic(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to save API token: %v", err)
+	}
+	return token, nil
+}
+
+// syncAPIServer stops the running API server and starts a new one when the
+// settings enable it
+func (a *App) syncAPIServer() {
+	a.mu.Lock()
+	running := a.api
+	a.api = nil
+	settings := a.settings.API
+	a.mu.Unlock()
+
+	if running != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		running.server.Shutdown(ctx)
+		cancel()
+		a.logger.Info("API server stopped", nil)
+	}
+	if !settings.Enabled {
+		return
+	}
+
+	server, err := a.startAPIServer(settings.Port)
+	if err != nil {
+		a.logger.Error("failed to start API server", map[string]interface{}{"port": settings.Port, "error": err.Error()})
+		return
+	}
+	a.mu.Lock()
+	a.api = server
+	a.mu.Unlock()
+	a.logger.Info("API server started", map[string]interface{}{"url": server.url})
+}
+
+// startAPIServer listens on localhost:port and serves the API in the background
+func (a *App) startAPIServer(port int) (*apiServer, error) {
+	token, err := loadAPIToken()
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %d: %v", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", a.handleAPICreateJob)
+	mux.HandleFunc("GET /jobs/{id}", a.handleAPIGetJob)
+	mux.HandleFunc("DELETE /jobs/{id}", a.handleAPICancelJob)
+	mux.HandleFunc("GET /jobs/{id}/events", a.handleAPIJobEvents)
+
+	s := &apiServer{
+		server: &http.Server{Handler: requireAPIToken(token, mux), ReadHeaderTimeout: 10 * time.Second},
+		url:    "http://" + listener.Addr().String(),
+		token:  token,
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logger.Error("API server stopped unexpectedly", map[string]interface{}{"error": err.Error()})
+		}
+	}()
+	return s, nil
+}
+
+// requireAPIToken rejects requests without the token, given either as a
+// bearer token or, for EventSource clients that cannot set headers, as the
+// token query parameter
+func requireAPIToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if given == "" || given == r.Header.Get("Authorization") {
+			given = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			writeAPIError(w, http.StatusUnauthorized, "missing or invalid API token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeAPIJSON writes v as the JSON response body
+func writeAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError writes an error response as {"error": message}
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, map[string]string{"error": message})
+}
+
+// queuedJob returns the queue entry of a job
+func (a *App) queuedJob(jobID string) (QueuedJob, bool) {
+	for _, job := range a.jobQueue().State().Jobs {
+		if job.JobID == jobID {
+			return job, true
+		}
+	}
+	return QueuedJob{}, false
+}
+
+// isFinishedJobState reports whether a queued job will not change state again
+func isFinishedJobState(state string) bool {
+	return state != jobStatePending && state != jobStateRunning
+}
+
+// handleAPICreateJob enqueues the ProcessVideoRequest in the body and
+// responds with the queued job. Paths must be absolute, since the caller's
+// working directory is unknown.
+func (a *App) handleAPICreateJob(w http.ResponseWriter, r *http.Request) {
+	var request ProcessVideoRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if !filepath.IsAbs(request.InputPath) || !filepath.IsAbs(request.OutputPath) {
+		writeAPIError(w, http.StatusBadRequest, "input_path and output_path must be absolute paths")
+		return
+	}
+	if request.Config == "" {
+		request.Config = "{}"
+	}
+	// The server assigns IDs so clients cannot collide with each other's jobs
+	request.JobID = ""
+
+	jobID := a.jobQueue().Enqueue([]ProcessVideoRequest{request})[0]
+	a.logger.Info("job submitted over the API", map[string]interface{}{"job_id": jobID, "input_path": request.InputPath})
+
+	job, _ := a.queuedJob(jobID)
+	w.Header().Set("Location", "/jobs/"+jobID)
+	writeAPIJSON(w, http.StatusAccepted, job)
+}
+
+// handleAPIGetJob responds with the queue entry of a job
+func (a *App) handleAPIGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := a.queuedJob(r.PathValue("id"))
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, job)
+}
+
+// handleAPICancelJob cancels a pending or running job
+func (a *App) handleAPICancelJob(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	job, ok := a.queuedJob(jobID)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	if isFinishedJobState(job.State) {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("job already %s", job.State))
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, a.CancelProcessing(jobID))
+}
+
+// handleAPIJobEvents streams a job's events as server-sent events. The
+// queue entry is sent as a "state" event on connect and whenever it changes;
+// progress and log events of the job are sent under their own topic. The
+// stream ends once the job has finished.
+func (a *App) handleAPIJobEvents(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("id")
+	job, ok := a.queuedJob(jobID)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	events := make(chan emittedEvent, apiEventBuffer)
+	unsubscribe := a.subscribeEvents(func(topic string, data interface{}) {
+		select {
+		case events <- emittedEvent{topic: topic, data: data}:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(topic string, data interface{}) {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", topic, payload)
+		flusher.Flush()
+	}
+
+	// Re-read the state after subscribing so no change is missed in between
+	job, _ = a.queuedJob(jobID)
+	send("state", job)
+	lastState := job.State
+
+	heartbeat := time.NewTicker(apiHeartbeatInterval)
+	defer heartbeat.Stop()
+	for !isFinishedJobState(lastState) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case event := <-events:
+			if event.topic == "queue:updated" {
+				state, ok := event.data.(QueueState)
+				if !ok {
+					continue
+				}
+				for _, queued := range state.Jobs {
+					if queued.JobID == jobID && queued.State != lastState {
+						lastState = queued.State
+						send("state", queued)
+					}
+				}
+				continue
+			}
+			if eventJobID(event.data) == jobID {
+				send(event.topic, event.data)
+			}
+		}
+	}
+}
+
+// eventJobID returns the job_id field of an event payload, or "" if it has none
+func eventJobID(data interface{}) string {
+	if jobID, ok := data.(string); ok {
+		return jobID
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	var record struct {
+		JobID string `json:"job_id"`
+	}
+	if json.Unmarshal(payload, &record) != nil {
+		return ""
+	}
+	return record.JobID
+}
+
+// GetAPIServerInfo returns the address and token of the HTTP API, or
+// Running false when it is disabled or failed to start
+func (a *App) GetAPIServerInfo() APIServerInfo {
+	a.mu.RLock()
+	server := a.api
+	a.mu.RUnlock()
+
+	if server == nil {
+		return APIServerInfo{}
+	}
+	tokenPath, _ := apiTokenPath()
+	return APIServerInfo{Running: true, URL: server.url, Token: server.token, TokenPath: tokenPath}
+}
@@ -2,30 +2,182 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
-	ctx context.Context
+	ctx          context.Context
+	contextReady chan struct{} // closed once startup has stored ctx
+	config       AppConfig
+	logger       Logger
+	events       *EventReplayBuffer
+	onEvent      func(topic string, data interface{}) // also receives emitted events, used by the CLI
+
+	mu             sync.RWMutex
+	workingDir     string // overrides os.Getwd() when set
+	settings       Settings
+	embeddedDir    string // where the backend shipped in the binary was extracted
+	outputWatches  *outputWatches
+	lastError      *ProcessingErrorRecord
+	jobs           map[string]*runningJob // job ID -> backend process, while running
+	claimedOutputs map[string]bool        // output paths reserved by running jobs
+	backend        *BackendManager        // persistent worker, nil when unavailable
+	queue          *JobQueue              // created on first use
+	persistStop    chan struct{}          // stops the periodic queue save, nil when not running
+	history        *HistoryStore          // opened on first use
+	watches        *watchFolders          // loaded on first use
+	api            *apiServer             // running while enabled in the settings
+	backendVersion *versionProbe          // last result of GetBackendVersion
+	gpus           *gpuProbe              // last successful DetectGPUs result
+
+	networkRequirements map[string][]string // operation name -> required host:port entries
+
+	eventLogMu sync.Mutex
+	eventLog   *os.File // set while event logging is enabled
+
+	subscribersMu  sync.Mutex
+	subscribers    map[int]func(topic string, data interface{}) // see subscribeEvents
+	nextSubscriber int
 }
 
-// NewApp creates a new App application struct
+// NewApp creates a new App application struct. Logs go to stderr and to a
+// rotating file under the user data directory.
 func NewApp() *App {
-	return &App{}
+	dir, err := logDir()
+	var logFile *rotatingFile
+	if err == nil {
+		logFile, err = newRotatingFile(filepath.Join(dir, logFileName), maxLogFileSize, maxLogBackups)
+	}
+	if err != nil {
+		app := NewAppWithLogger(NewJSONLogger(os.Stderr))
+		app.logger.Warn("file logging disabled", map[string]interface{}{"error": err.Error()})
+		return app
+	}
+	return NewAppWithLogger(NewJSONLogger(io.MultiWriter(os.Stderr, logFile)))
+}
+
+// NewAppWithLogger creates a new App that writes its logs to the given logger
+func NewAppWithLogger(logger Logger) *App {
+	return &App{
+		config:   DefaultAppConfig(),
+		settings: DefaultSettings(),
+		logger:   logger,
+		events:   NewEventReplayBuffer(eventReplayCapacity, eventReplayMaxAge),
+
+		contextReady: make(chan struct{}),
+	}
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	close(a.contextReady)
+
+	a.loadSettings()
+	a.applyHistoryRetention()
+	a.prepareEmbeddedBackend()
+
+	a.announceRecoveredResult()
+
+	if err := a.SetupApplicationMenu(); err != nil {
+		a.logger.Warn("failed to set up application menu", map[string]interface{}{"error": err.Error()})
+	}
+
+	a.registerFileDrop()
+	a.detectInterruptedJobs()
+	a.restoreQueue()
+	a.restoreWatchFolders()
+	a.syncAPIServer()
+
+	// Launching the worker loads the Python environment, which can take a while
+	go a.startBackendWorker()
+
+	// The banner runs external version commands, so keep it off the startup path
+	go func() {
+		if err := a.LogStartupBanner(); err != nil {
+			a.logger.Warn("failed to log startup banner", map[string]interface{}{"error": err.Error()})
+		}
+	}()
+}
+
+// contextReadyTimeout is how long methods that need the Wails context wait for startup
+const contextReadyTimeout = 5 * time.Second
+
+// errAppNotReady is returned by methods called before startup has completed
+var errAppNotReady = errors.New("AppNotReady: the application has not finished starting up")
+
+// WaitForContext blocks until startup has completed or the timeout expires
+func (a *App) WaitForContext(timeout time.Duration) error {
+	select {
+	case <-a.contextReady:
+		return nil
+	case <-time.After(timeout):
+		return errAppNotReady
+	}
+}
+
+// isContextReady reports whether startup has completed, without blocking
+func (a *App) isContextReady() bool {
+	select {
+	case <-a.contextReady:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetWorkingDirectory sets the directory used to resolve the backend script path
+func (a *App) SetWorkingDirectory(dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid working directory %s: %v", dir, err)
+	}
+
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return fmt.Errorf("cannot access working directory %s: %v", absDir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("working directory is not a directory: %s", absDir)
+	}
+
+	a.mu.Lock()
+	a.workingDir = absDir
+	a.mu.Unlock()
+	return nil
+}
+
+// GetWorkingDirectory returns the configured working directory, falling back to
+// the process working directory. It returns an empty string if neither is available.
+func (a *App) GetWorkingDirectory() string {
+	a.mu.RLock()
+	dir := a.workingDir
+	a.mu.RUnlock()
+	if dir != "" {
+		return dir
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return dir
 }
 
 // Greet returns a greeting for the given name
@@ -38,20 +190,216 @@ type ProcessVideoRequest struct {
 	InputPath  string `json:"input_path"`
 	OutputPath string `json:"output_path"`
 	Config     string `json:"config"` // JSON string containing analysis parameters
+	// AnalysisConfig is the typed form of Config. When set it is validated and
+	// replaces Config.
+	AnalysisConfig *AnalysisConfig `json:"analysis_config,omitempty"`
+	// CreateOutputDirIfMissing controls whether a missing output directory is
+	// created. Defaults to true when omitted.
+	CreateOutputDirIfMissing *bool `json:"create_output_dir_if_missing,omitempty"`
+	// Deadline is an optional wall-clock time by which processing must finish
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// Env holds extra environment variables passed to the backend process
+	Env map[string]string `json:"env,omitempty"`
+	// Description is a human-readable job label. Defaults to
+	// "Processing <input file name>".
+	Description string `json:"description,omitempty"`
+	// WorkingDir overrides the directory the backend runs in. It must contain
+	// backend/process_video.py.
+	WorkingDir string `json:"working_dir,omitempty"`
+	// FallbackConfig is a lighter configuration retried once when processing
+	// runs out of memory or time
+	FallbackConfig string `json:"fallback_config,omitempty"`
+	// JobID identifies the job for CancelProcessing. Generated when omitted.
+	JobID string `json:"job_id,omitempty"`
+	// TimeoutSeconds overrides AppConfig.JobTimeoutSeconds for this job. Zero
+	// uses the default; a negative value disables the timeout.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// OverwritePolicy decides what happens when OutputPath already exists:
+	// "overwrite" (the default), "fail", "auto-rename" or "skip"
+	OverwritePolicy string `json:"overwrite_policy,omitempty"`
+	// RetryAttempts overrides Settings.RetryAttempts for this job. Zero uses
+	// the setting; a negative value disables retries.
+	RetryAttempts int `json:"retry_attempts,omitempty"`
+	// SkipCache processes the video even if a cached result matches
+	SkipCache bool `json:"skip_cache,omitempty"`
+	// RunAt delays a queued job until the given time. Ignored by ProcessVideo.
+	RunAt *time.Time `json:"run_at,omitempty"`
+	// OffHoursOnly lets a queued job start only inside the off-hours window
+	// from the settings. Ignored by ProcessVideo.
+	OffHoursOnly bool `json:"off_hours_only,omitempty"`
+	// StartTime and EndTime limit processing to a segment of the input, in
+	// seconds. Zero EndTime means the end of the video.
+	StartTime float64 `json:"start_time,omitempty"`
+	EndTime   float64 `json:"end_time,omitempty"`
+	// Metadata holds free-form tags, such as "priority": "high", for
+	// selecting queued jobs with FilterQueueByMetadata and RunJobsWithTag
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
+// maxDescriptionLength is the exclusive upper bound on ProcessVideoRequest.Description
+const maxDescriptionLength = 500
+
 // ProcessVideoResponse represents the response from video processing
 type ProcessVideoResponse struct {
-	Status           string `json:"status"`
-	OutputVideoPath  string `json:"output_video_path,omitempty"`
-	DatabaseID       string `json:"database_id,omitempty"`
-	Message          string `json:"message"`
-	ErrorType        string `json:"error_type,omitempty"`
+	Status          string `json:"status"`
+	OutputVideoPath string `json:"output_video_path,omitempty"`
+	DatabaseID      string `json:"database_id,omitempty"`
+	DatabasePath    string `json:"database_path,omitempty"` // analysis database holding DatabaseID
+	Message         string `json:"message"`
+	ErrorType       string `json:"error_type,omitempty"`
+	JobID           string `json:"job_id,omitempty"`
+	Cached          bool   `json:"cached,omitempty"`   // reused from an earlier run with the same input and configuration
+	Attempts        int    `json:"attempts,omitempty"` // backend runs made, including retries after transient failures
+	// OutputWatchID identifies the watch on the output file, for UnwatchOutputFile
+	OutputWatchID string `json:"output_watch_id,omitempty"`
+
+	// Computed by EnrichResponse for successful runs
+	OutputDurationSeconds float64 `json:"output_duration_seconds,omitempty"`
+	OutputSizeBytes       int64   `json:"output_size_bytes,omitempty"`
+	OutputResolution      string  `json:"output_resolution,omitempty"`
+	OutputCodec           string  `json:"output_codec,omitempty"`
 }
 
 // ProcessVideo processes a video file using the Python backend
 // This function will be called from the Svelte UI
 func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
+	startedAt := time.Now()
+	// Checked before the default description is filled in
+	invalid := checkDescription(request)
+	request.Description = jobDescription(request)
+	if request.JobID == "" {
+		request.JobID = generateID()
+	}
+
+	var response ProcessVideoResponse
+	processed := false // whether the backend ran with the request's own configuration
+	if invalid != nil {
+		response = *invalid
+	} else if resolved, early := a.applyOverwritePolicy(request); early != nil {
+		response = *early
+	} else {
+		request = resolved
+		defer a.releaseOutputPath(request.OutputPath)
+		cached, ok := ProcessVideoResponse{}, false
+		if !request.SkipCache {
+			cached, ok = a.lookupCachedResult(request)
+		}
+		if ok {
+			response = cached
+		} else {
+			response = a.processWithRetries(request)
+			processed = true
+		}
+	}
+
+	// Retry once with the fallback configuration for resource-related failures
+	if response.Status == "error" && request.FallbackConfig != "" &&
+		(response.ErrorType == "MemoryError" || response.ErrorType == "TimeoutError") {
+		originalError := response.ErrorType
+		a.logger.Warn("retrying with fallback configuration", map[string]interface{}{
+			"job_id":     request.JobID,
+			"phase":      "fallback",
+			"input_path": request.InputPath,
+			"error_type": originalError,
+		})
+
+		fallback := request
+		fallback.Config = request.FallbackConfig
+		fallback.FallbackConfig = ""
+		response = a.processVideo(fallback)
+		processed = false
+
+		if response.Status == "success" {
+			response.Message = fmt.Sprintf("%s The fallback configuration was used after a %s.", response.Message, originalError)
+		} else {
+			response.Message = fmt.Sprintf("Fallback configuration also failed after a %s: %s", originalError, response.Message)
+		}
+	}
+
+	// Report where the output went, which differs from the request after an auto-rename
+	if response.Status == "success" && response.OutputVideoPath == "" {
+		response.OutputVideoPath = request.OutputPath
+	}
+	if processed && response.Status == "success" {
+		a.storeCachedResult(request, response)
+	}
+	response = a.EnrichResponse(response)
+	response.JobID = request.JobID
+	a.recordProcessingResult(request, response)
+	a.recordHistory(request, response, startedAt)
+	go a.notifyJobFinished(request, response)
+
+	if response.Status == "success" {
+		a.logger.Info("video processing completed", map[string]interface{}{
+			"job_id":      request.JobID,
+			"phase":       "completed",
+			"input_path":  request.InputPath,
+			"output_path": response.OutputVideoPath,
+			"database_id": response.DatabaseID,
+		})
+	} else if response.Status == "cancelled" {
+		a.logger.Info("video processing cancelled", map[string]interface{}{
+			"job_id":     request.JobID,
+			"phase":      "cancelled",
+			"input_path": request.InputPath,
+		})
+	} else if response.Status == "skipped" {
+		a.logger.Info("video processing skipped", map[string]interface{}{
+			"job_id":      request.JobID,
+			"phase":       "skipped",
+			"input_path":  request.InputPath,
+			"output_path": response.OutputVideoPath,
+		})
+	} else {
+		a.logger.Error("video processing failed", map[string]interface{}{
+			"job_id":     request.JobID,
+			"phase":      "failed",
+			"input_path": request.InputPath,
+			"error_type": response.ErrorType,
+			"message":    response.Message,
+		})
+	}
+
+	return response
+}
+
+// EnrichResponse fills in the output file size, duration, resolution and codec
+// of a successful response. Values that cannot be determined are left empty.
+func (a *App) EnrichResponse(response ProcessVideoResponse) ProcessVideoResponse {
+	if response.Status != "success" || response.OutputVideoPath == "" {
+		return response
+	}
+
+	if info, err := os.Stat(response.OutputVideoPath); err == nil {
+		response.OutputSizeBytes = info.Size()
+	}
+	if duration, err := probeDuration(response.OutputVideoPath); err == nil {
+		response.OutputDurationSeconds = duration
+	}
+	if streams, err := probeStreams(response.OutputVideoPath); err == nil {
+		for _, stream := range streams {
+			if stream.CodecType == "video" {
+				response.OutputResolution = fmt.Sprintf("%dx%d", stream.Width, stream.Height)
+				response.OutputCodec = stream.CodecName
+				break
+			}
+		}
+	}
+
+	return response
+}
+
+// processVideo runs a single backend invocation for the request
+func (a *App) processVideo(request ProcessVideoRequest) ProcessVideoResponse {
+	// Refuse to start work that cannot finish in time
+	if request.Deadline != nil && !time.Now().Before(*request.Deadline) {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "DeadlineExceededError",
+			Message:   fmt.Sprintf("Processing deadline %s has already passed.", request.Deadline.Format(time.RFC3339)),
+		}
+	}
+
 	// Enhanced input validation
 	if request.InputPath == "" {
 		return ProcessVideoResponse{
@@ -60,7 +408,7 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   "Input video path is required. Please select a video file.",
 		}
 	}
-	
+
 	if request.OutputPath == "" {
 		return ProcessVideoResponse{
 			Status:    "error",
@@ -68,7 +416,26 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   "Output path is required. Please specify where to save the processed video.",
 		}
 	}
-	
+
+	if request.AnalysisConfig != nil {
+		if err := request.AnalysisConfig.Validate(); err != nil {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "ConfigurationError",
+				Message:   fmt.Sprintf("Invalid analysis parameters: %v. Please adjust your parameter settings.", err),
+			}
+		}
+		config, err := request.AnalysisConfig.Marshal()
+		if err != nil {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "ConfigurationError",
+				Message:   fmt.Sprintf("Failed to encode analysis parameters: %v.", err),
+			}
+		}
+		request.Config = config
+	}
+
 	if request.Config == "" {
 		return ProcessVideoResponse{
 			Status:    "error",
@@ -76,7 +443,15 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   "Analysis configuration is required. Please check your parameter settings.",
 		}
 	}
-	
+
+	if err := validateBackendEnv(request.Env); err != nil {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "ValidationError",
+			Message:   fmt.Sprintf("Invalid environment configuration: %v.", err),
+		}
+	}
+
 	// Validate input file exists and is accessible
 	if _, err := os.Stat(request.InputPath); os.IsNotExist(err) {
 		return ProcessVideoResponse{
@@ -91,31 +466,62 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   fmt.Sprintf("Cannot access input video file: %s. Error: %v", request.InputPath, err),
 		}
 	}
-	
-	// Validate config is valid JSON
-	var configTest interface{}
-	if err := json.Unmarshal([]byte(request.Config), &configTest); err != nil {
+
+	// Validate legacy string configs against the typed parameters; the string
+	// itself is passed on unchanged
+	analysisConfig, err := ParseAnalysisConfig(request.Config)
+	if err != nil {
 		return ProcessVideoResponse{
 			Status:    "error",
 			ErrorType: "ConfigurationError",
 			Message:   fmt.Sprintf("Invalid configuration format: %v. Please reset parameters and try again.", err),
 		}
 	}
-	
-	// Get the current working directory to construct the path to the Python script
-	workingDir, err := os.Getwd()
-	if err != nil {
+	if err := analysisConfig.Validate(); err != nil {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "ConfigurationError",
+			Message:   fmt.Sprintf("Invalid analysis parameters: %v. Please adjust your parameter settings.", err),
+		}
+	}
+
+	// Resolve the backend directory used to construct the path to the Python script
+	backendDir := a.backendDir()
+	if backendDir == "" {
 		return ProcessVideoResponse{
 			Status:    "error",
 			ErrorType: "SystemError",
-			Message:   fmt.Sprintf("System error: Failed to get working directory: %v", err),
+			Message:   "System error: Failed to get working directory. Please set it explicitly and try again.",
 		}
 	}
-	
+
 	// Construct the path to the Python script (relative to backend directory)
-	scriptPath := "process_video.py"
-	fullScriptPath := filepath.Join(workingDir, "backend", "process_video.py")
-	
+	scriptPath := backendScriptName
+	fullScriptPath := filepath.Join(backendDir, scriptPath)
+	commandDir := backendDir
+
+	// A per-request working directory must carry its own copy of the backend
+	if request.WorkingDir != "" {
+		info, err := os.Stat(request.WorkingDir)
+		if err != nil || !info.IsDir() {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "InvalidWorkingDirError",
+				Message:   fmt.Sprintf("Working directory does not exist or is not a directory: %s.", request.WorkingDir),
+			}
+		}
+		fullScriptPath = backendScriptPath(request.WorkingDir)
+		if _, err := os.Stat(fullScriptPath); err != nil {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "InvalidWorkingDirError",
+				Message:   fmt.Sprintf("Working directory %s does not contain the backend script at %s.", request.WorkingDir, fullScriptPath),
+			}
+		}
+		scriptPath = fullScriptPath
+		commandDir = request.WorkingDir
+	}
+
 	// Check if the Python script exists
 	if _, err := os.Stat(fullScriptPath); os.IsNotExist(err) {
 		return ProcessVideoResponse{
@@ -124,13 +530,21 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   fmt.Sprintf("Backend processing script not found at: %s. Please check your installation.", fullScriptPath),
 		}
 	}
-	
+
 	// Check if output directory exists and is writable
 	outputDir := filepath.Dir(request.OutputPath)
 	if outputDir != "" {
 		if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+			if request.CreateOutputDirIfMissing != nil && !*request.CreateOutputDirIfMissing {
+				return ProcessVideoResponse{
+					Status:    "error",
+					ErrorType: "DirectoryNotFoundError",
+					Message:   fmt.Sprintf("Output directory does not exist: %s. Please create it or choose another location.", outputDir),
+				}
+			}
+
 			// Try to create the directory
-			if err := os.MkdirAll(outputDir, 0755); err != nil {
+			if err := os.MkdirAll(outputDir, a.config.OutputDirMode); err != nil {
 				return ProcessVideoResponse{
 					Status:    "error",
 					ErrorType: "FileSystemError",
@@ -139,59 +553,162 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			}
 		}
 	}
-	
+
+	if response := checkTrimRange(request); response != nil {
+		return *response
+	}
+	if response := a.checkPinnedDevice(); response != nil {
+		return *response
+	}
+	if response := a.checkBackendCompatibility(); response != nil {
+		return *response
+	}
+
+	// Refuse to start when the output volume cannot hold the result. The check
+	// is skipped if the input size or the free space cannot be read.
+	if estimate, err := estimateOutputBytes(request.InputPath); err == nil {
+		if space, err := a.CheckDiskSpace(request.OutputPath, estimate); err == nil && !space.Sufficient {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "DiskSpaceError",
+				Message: fmt.Sprintf("Not enough disk space in %s: %s free, about %s needed. Please free up space or choose another location.",
+					space.Path, formatBytes(space.FreeBytes), formatBytes(space.RequiredBytes)),
+			}
+		}
+	}
+
+	// The backend writes to a temporary file next to the destination, which is
+	// renamed into place only after it checks out. Whatever is left of it when
+	// the job fails or is cancelled is removed.
+	partialPath := partialOutputPath(request.OutputPath, request.JobID)
+	defer os.Remove(partialPath)
+	backendRequest := request
+	backendRequest.OutputPath = partialPath
+
 	// Prepare the command arguments according to the contract
-	args := []string{
-		scriptPath,
-		"--input", request.InputPath,
-		"--output", request.OutputPath,
-		"--config", request.Config,
-	}
-	
-	// Add debug flags if environment variable is set
-	if os.Getenv("PYTHON_DEBUG") == "true" {
-		args = append(args, "--debug")
-		// Optionally wait for debugger
-		if os.Getenv("PYTHON_DEBUG_WAIT") == "true" {
-			args = append(args, "--debug-wait")
-		}
-		// Custom debug port
-		if port := os.Getenv("PYTHON_DEBUG_PORT"); port != "" {
-			args = append(args, "--debug-port", port)
-		}
-	}
-	
-	// Execute the Python script using uv run for proper virtual environment handling
-	uvArgs := append([]string{"run", "python"}, args...)
-	cmd := exec.Command("uv", uvArgs...)
-	cmd.Dir = filepath.Join(workingDir, "backend") // Set working directory to backend folder
-	
-	// Capture both stdout and stderr
-	stdout, err := cmd.Output()
-	var stderr []byte
-	
-	// Handle execution errors
-	cmdErr := err
-	
+	builder := NewBackendCommandBuilder(scriptPath).
+		WithInput(request.InputPath).
+		WithOutput(partialPath).
+		WithConfig(request.Config).
+		WithTrim(request.StartTime, request.EndTime)
+
+	// Add debug flags if backend debugging is enabled in the settings
+	if debug := a.debugSettings(); debug.PythonDebug {
+		builder.WithDebug(debug.WaitForClient, debug.Port)
+	}
+	args := builder.Build()
+
+	// Execute the Python script with the configured interpreter, uv run by default
+	ctx := context.Background()
+	if request.Deadline != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, *request.Deadline)
+		defer cancel()
+	}
+	timeout := a.jobTimeout(request)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	worker := a.reserveWorker(request)
+
+	a.logger.Info("starting backend", map[string]interface{}{
+		"job_id":      request.JobID,
+		"phase":       "backend_start",
+		"description": request.Description,
+		"input_path":  request.InputPath,
+		"output_path": request.OutputPath,
+		"script_dir":  commandDir,
+		"worker":      worker != nil,
+	})
+
+	job := a.registerJob(request.JobID, partialPath)
+	defer a.unregisterJob(request.JobID)
+	a.persistJobDescriptor(request, partialPath)
+	defer removeJobDescriptor(request.JobID)
+
+	var stdout, stderr []byte
+	var cmdErr error
+	if worker != nil {
+		stdout, stderr, cmdErr = worker.ProcessVideo(ctx, backendRequest, job)
+		// The worker died after it was reserved, before it received the job
+		if errors.Is(cmdErr, errWorkerNotRunning) {
+			a.logger.Warn("backend worker stopped, running the job in its own process", map[string]interface{}{
+				"job_id": request.JobID,
+				"error":  cmdErr.Error(),
+			})
+			worker = nil
+		}
+	}
+	if worker == nil {
+		python, pythonArgs := a.pythonCommand(args...)
+		cmd := exec.CommandContext(ctx, python, pythonArgs...)
+		cmd.Dir = commandDir // Backend folder, or the per-request working directory
+		cmd.Env = a.backendEnv(request)
+		setProcessGroup(cmd)
+		if a.GetSettings().LowPriority {
+			setLowPriority(cmd)
+		}
+		cmd.Cancel = func() error { return terminateProcessTree(cmd) }
+		cmd.WaitDelay = processKillGracePeriod
+
+		// Stream stdout for progress records while capturing stderr
+		stdout, stderr, cmdErr = a.runBackendCommand(cmd, request, job)
+	}
+
+	if a.isJobCancelled(job) {
+		return a.cancelledResponse(request.JobID, job)
+	}
+
+	// The process was killed because the deadline or the job timeout passed
+	if cmdErr != nil && ctx.Err() == context.DeadlineExceeded {
+		if request.Deadline != nil && !time.Now().Before(*request.Deadline) {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "DeadlineExceededError",
+				Message:   fmt.Sprintf("Processing did not finish before the deadline %s and was stopped.", request.Deadline.Format(time.RFC3339)),
+			}
+		}
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "TimeoutError",
+			Message:   fmt.Sprintf("Processing did not finish within %s and was stopped. Try a shorter video or a longer timeout.", timeout),
+		}
+	}
+
 	// Handle execution errors with detailed messages
 	if cmdErr != nil {
-		// Extract stderr from the error if it's an ExitError
-		if exitError, ok := cmdErr.(*exec.ExitError); ok {
-			stderr = exitError.Stderr
-		}
-		
 		stderrStr := string(stderr)
-		
-		// Try to parse stderr as JSON error response first
+		a.logger.Error("backend execution failed", map[string]interface{}{
+			"job_id":     request.JobID,
+			"phase":      "backend_exit",
+			"input_path": request.InputPath,
+			"error":      cmdErr.Error(),
+			"stderr":     stderrExcerpt(stderrStr),
+		})
+
+		// A backend that died without writing its error JSON crashed, for
+		// example in a native library or by the system's out-of-memory killer
+		if lastJSONLine(stderr) == nil && isBackendCrash(cmdErr) {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "BackendCrashError",
+				Message:   fmt.Sprintf("The backend stopped unexpectedly: %v.", cmdErr),
+			}
+		}
+
+		// Try to parse the error JSON written after any log output first
 		var errorResponse ProcessVideoResponse
-		if len(stderr) > 0 && json.Unmarshal(stderr, &errorResponse) == nil {
+		if line := lastJSONLine(stderr); line != nil && json.Unmarshal(line, &errorResponse) == nil && errorResponse.Status != "" {
 			// Enhance the error message with more context
 			if errorResponse.Message != "" {
 				errorResponse.Message = fmt.Sprintf("Processing failed: %s", errorResponse.Message)
 			}
 			return errorResponse
 		}
-		
+
 		// Handle specific error types based on stderr content
 		if len(stderrStr) > 0 {
 			// Check for common error patterns
@@ -219,6 +736,12 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 					ErrorType: "MemoryError",
 					Message:   "Insufficient memory to process the video. Try with a smaller video file or close other applications.",
 				}
+			} else if isGPUFailure(stderrStr) {
+				return ProcessVideoResponse{
+					Status:    "error",
+					ErrorType: "GPUNotAvailableError",
+					Message:   fmt.Sprintf("Hardware acceleration failed. Choose another device or CPU mode in the settings. Details: %s", stderrStr),
+				}
 			} else if contains(stderrStr, "cv2.error") || contains(stderrStr, "OpenCV") {
 				return ProcessVideoResponse{
 					Status:    "error",
@@ -226,7 +749,7 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 					Message:   fmt.Sprintf("Video processing error. The video file may be corrupted or in an unsupported format. Details: %s", stderrStr),
 				}
 			}
-			
+
 			// Generic error with stderr content
 			return ProcessVideoResponse{
 				Status:    "error",
@@ -234,7 +757,7 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 				Message:   fmt.Sprintf("Processing failed with error: %s", stderrStr),
 			}
 		}
-		
+
 		// Error without stderr content
 		return ProcessVideoResponse{
 			Status:    "error",
@@ -242,8 +765,13 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   fmt.Sprintf("Python script execution failed: %v", cmdErr),
 		}
 	}
-	
+
 	// Handle successful execution
+	// Kept until the result is handled, so a crash meanwhile does not lose it
+	if len(stdout) > 0 {
+		a.persistLastResult(request.JobID, stdout)
+		defer a.clearLastResult(request.JobID)
+	}
 	if len(stdout) == 0 {
 		return ProcessVideoResponse{
 			Status:    "error",
@@ -251,17 +779,23 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   "No output received from processing script. The process may have failed silently.",
 		}
 	}
-	
+
 	// Parse the successful response from stdout
 	var response ProcessVideoResponse
 	if err := json.Unmarshal(stdout, &response); err != nil {
+		a.logger.Error("failed to parse backend output", map[string]interface{}{
+			"job_id": request.JobID,
+			"phase":  "parse_result",
+			"error":  err.Error(),
+			"stdout": stderrExcerpt(string(stdout)),
+		})
 		return ProcessVideoResponse{
 			Status:    "error",
 			ErrorType: "ParseError",
 			Message:   fmt.Sprintf("Failed to parse processing results: %v. Raw output: %s", err, string(stdout)),
 		}
 	}
-	
+
 	// Validate the response has required fields
 	if response.Status == "" {
 		return ProcessVideoResponse{
@@ -270,29 +804,55 @@ func (a *App) ProcessVideo(request ProcessVideoRequest) ProcessVideoResponse {
 			Message:   "Invalid response format from processing script.",
 		}
 	}
-	
+
 	// Enhance success message
 	if response.Status == "success" && response.Message == "" {
 		response.Message = "Video processing completed successfully."
 	}
-	
+
+	// Move the verified output to the requested path
+	if response.Status == "success" {
+		// Replacing a watched file would be reported as an outside deletion
+		a.unwatchOutputPath(request.OutputPath)
+		if err := finalizeOutput(partialPath, request.OutputPath); err != nil {
+			return ProcessVideoResponse{
+				Status:    "error",
+				ErrorType: "OutputError",
+				Message:   fmt.Sprintf("The processed video failed verification and was discarded: %v", err),
+			}
+		}
+		response.OutputVideoPath = request.OutputPath
+		// The backend keeps one database per input, so IDs repeat across videos
+		if response.DatabaseID != "" {
+			response.DatabasePath = analysisDBPath(request.InputPath)
+		}
+	}
+
+	// Track the output so the UI learns if it disappears later
+	if response.Status == "success" && response.OutputVideoPath != "" {
+		watcherID, err := a.WatchOutputFile(response.OutputVideoPath)
+		if err != nil {
+			a.logger.Warn("failed to watch output file", map[string]interface{}{
+				"job_id":      request.JobID,
+				"output_path": response.OutputVideoPath,
+				"error":       err.Error(),
+			})
+		}
+		response.OutputWatchID = watcherID
+	}
+
 	return response
 }
 
 // SelectVideoFile opens a file dialog to select a video file
 func (a *App) SelectVideoFile() (string, error) {
+	if err := a.WaitForContext(contextReadyTimeout); err != nil {
+		return "", err
+	}
+
 	options := runtime.OpenDialogOptions{
-		Title: "Select Video File",
-		Filters: []runtime.FileFilter{
-			{
-				DisplayName: "Video Files",
-				Pattern:     "*.mp4;*.avi;*.mov;*.mkv;*.wmv;*.flv;*.webm",
-			},
-			{
-				DisplayName: "All Files",
-				Pattern:     "*",
-			},
-		},
+		Title:   "Select Video File",
+		Filters: videoFileFilters(),
 	}
 
 	filePath, err := runtime.OpenFileDialog(a.ctx, options)
@@ -303,7 +863,205 @@ func (a *App) SelectVideoFile() (string, error) {
 	return filePath, nil
 }
 
+// videoFileFilters returns the filters used by the video open dialogs
+func videoFileFilters() []runtime.FileFilter {
+	patterns := make([]string, len(videoExtensions))
+	for i, ext := range videoExtensions {
+		patterns[i] = "*" + ext
+	}
+
+	return []runtime.FileFilter{
+		{
+			DisplayName: "Video Files",
+			Pattern:     strings.Join(patterns, ";"),
+		},
+		{
+			DisplayName: "All Files",
+			Pattern:     "*",
+		},
+	}
+}
+
+// RejectedFile is a selected path that cannot be used as an input
+type RejectedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// VideoFileSelection is the result of SelectVideoFiles
+type VideoFileSelection struct {
+	Files    []string       `json:"files"`
+	Rejected []RejectedFile `json:"rejected"`
+}
+
+// SelectVideoFiles opens a file dialog to select several video files. Duplicate
+// paths are dropped and files that cannot be read are returned in Rejected.
+// Both lists are empty if the dialog was cancelled.
+func (a *App) SelectVideoFiles() (VideoFileSelection, error) {
+	selection := VideoFileSelection{Files: []string{}, Rejected: []RejectedFile{}}
+	if err := a.WaitForContext(contextReadyTimeout); err != nil {
+		return selection, err
+	}
+
+	options := runtime.OpenDialogOptions{
+		Title:   "Select Video Files",
+		Filters: videoFileFilters(),
+	}
+
+	paths, err := runtime.OpenMultipleFilesDialog(a.ctx, options)
+	if err != nil {
+		return selection, fmt.Errorf("failed to open file dialog: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			selection.Rejected = append(selection.Rejected, RejectedFile{Path: path, Reason: err.Error()})
+			continue
+		}
+		if seen[absPath] {
+			continue
+		}
+		seen[absPath] = true
+
+		if err := checkReadableFile(absPath); err != nil {
+			selection.Rejected = append(selection.Rejected, RejectedFile{Path: absPath, Reason: err.Error()})
+			continue
+		}
+		selection.Files = append(selection.Files, absPath)
+	}
+
+	return selection, nil
+}
+
+// checkReadableFile returns an error unless path is a regular file that can be opened for reading
+func checkReadableFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access file: %v", err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot read file: %v", err)
+	}
+	return f.Close()
+}
+
+// outputVideoExtensions are the containers the backend can write, the first being the default
+var outputVideoExtensions = []string{".mp4", ".avi", ".mov"}
+
+// SelectOutputPath opens a save dialog to choose where the processed video is
+// written. The returned path always has a supported video extension; it is
+// empty if the dialog was cancelled.
+func (a *App) SelectOutputPath(defaultName string) (string, error) {
+	if err := a.WaitForContext(contextReadyTimeout); err != nil {
+		return "", err
+	}
+
+	if defaultName == "" {
+		defaultName = "output"
+	}
+	defaultName = withOutputVideoExtension(filepath.Base(defaultName))
+
+	options := runtime.SaveDialogOptions{
+		Title:            "Save Processed Video",
+		DefaultDirectory: a.GetSettings().DefaultOutputDir,
+		DefaultFilename:  defaultName,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "MP4 Video (*.mp4)", Pattern: "*.mp4"},
+			{DisplayName: "AVI Video (*.avi)", Pattern: "*.avi"},
+			{DisplayName: "QuickTime Video (*.mov)", Pattern: "*.mov"},
+		},
+		CanCreateDirectories: true,
+	}
+
+	filePath, err := runtime.SaveFileDialog(a.ctx, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to open save dialog: %v", err)
+	}
+	if filePath == "" {
+		return "", nil
+	}
+
+	return withOutputVideoExtension(filePath), nil
+}
+
+// withOutputVideoExtension appends the default extension unless the path
+// already ends in a supported output extension
+func withOutputVideoExtension(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, supported := range outputVideoExtensions {
+		if ext == supported {
+			return path
+		}
+	}
+	return path + outputVideoExtensions[0]
+}
+
+// processKillGracePeriod is how long a timed-out or cancelled backend may take
+// to exit after SIGTERM before it is killed
+const processKillGracePeriod = 10 * time.Second
+
+// jobTimeout returns how long the request may run, or zero for no limit
+func (a *App) jobTimeout(request ProcessVideoRequest) time.Duration {
+	seconds := request.TimeoutSeconds
+	if seconds == 0 {
+		a.mu.RLock()
+		seconds = a.config.JobTimeoutSeconds
+		a.mu.RUnlock()
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// checkDescription rejects a caller-supplied description that is too long. It
+// returns an error response, or nil when the description is acceptable.
+func checkDescription(request ProcessVideoRequest) *ProcessVideoResponse {
+	if utf8.RuneCountInString(request.Description) < maxDescriptionLength {
+		return nil
+	}
+	return &ProcessVideoResponse{
+		Status:    "error",
+		ErrorType: "ValidationError",
+		Message:   fmt.Sprintf("Job description must be shorter than %d characters.", maxDescriptionLength),
+	}
+}
+
+// jobDescription returns the request's description, or a default label derived
+// from the input file. Only a description the caller supplied is checked with
+// checkDescription, so the default is shortened to fit.
+func jobDescription(request ProcessVideoRequest) string {
+	if request.Description != "" || request.InputPath == "" {
+		return request.Description
+	}
+	description := []rune("Processing " + filepath.Base(request.InputPath))
+	if len(description) >= maxDescriptionLength {
+		description = append(description[:maxDescriptionLength-2], '…')
+	}
+	return string(description)
+}
+
+// backendScriptPath returns the location of the Python processing script for a working directory
+func backendScriptPath(workingDir string) string {
+	return filepath.Join(workingDir, "backend", backendScriptName)
+}
+
 // Helper function to check if a string contains a substring (case-insensitive)
 func contains(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
+
+// generateID returns a random hex identifier for jobs, watchers and similar handles
+func generateID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
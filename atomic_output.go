@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// partialOutputPath returns the temporary file the backend writes for a job.
+// It sits in the destination directory so the final rename stays on one volume,
+// and keeps the extension because the backend picks the container from it.
+func partialOutputPath(outputPath, jobID string) string {
+	dir, name := filepath.Split(outputPath)
+	ext := filepath.Ext(name)
+	return filepath.Join(dir, fmt.Sprintf(".%s.partial-%s%s", strings.TrimSuffix(name, ext), jobID, ext))
+}
+
+// finalOutputPath returns the output path a partial output path was made
+// for, or path itself when it is not a partial output path
+func finalOutputPath(path string) string {
+	dir, name := filepath.Split(path)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	i := strings.LastIndex(stem, ".partial-")
+	if i <= 0 || !strings.HasPrefix(stem, ".") {
+		return path
+	}
+	return filepath.Join(dir, stem[1:i]+ext)
+}
+
+// finalizeOutput checks the video written to partialPath and renames it to
+// outputPath. The file must not be empty and, when ffprobe is installed, must
+// have a readable duration.
+func finalizeOutput(partialPath, outputPath string) error {
+	info, err := os.Stat(partialPath)
+	if err != nil {
+		return fmt.Errorf("the backend did not write %s: %v", partialPath, err)
+	}
+	if info.Size() == 0 {
+		return errors.New("the backend wrote an empty file")
+	}
+	if _, err := exec.LookPath("ffprobe"); err == nil {
+		if _, err := probeDuration(partialPath); err != nil {
+			return fmt.Errorf("the written video cannot be read: %v", err)
+		}
+	}
+
+	if err := os.Rename(partialPath, outputPath); err != nil {
+		return fmt.Errorf("failed to move the output into place: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// auditLogFileName is the append-only audit trail under the app data directory
+const auditLogFileName = "audit.log"
+
+// auditLogMu serializes writers of the audit logs so records are never interleaved
+var auditLogMu sync.Mutex
+
+// AuditRecord is one line of the processing audit trail
+type AuditRecord struct {
+	Event       string    `json:"event"` // "started" or "completed"
+	Timestamp   time.Time `json:"timestamp"`
+	UserID      string    `json:"user_id"`
+	Reason      string    `json:"reason"`
+	JobID       string    `json:"job_id"`
+	Description string    `json:"description"`
+	InputPath   string    `json:"input_path"`
+	OutputPath  string    `json:"output_path"`
+	Status      string    `json:"status"`
+}
+
+// appendAuditRecord appends a record to the audit log. The file is only ever
+// opened for appending and is never truncated.
+func appendAuditRecord(record AuditRecord) error {
+	return appendAuditLine(auditLogFileName, record)
+}
+
+// appendAuditLine appends v as a JSON line to the named file under the app
+// data directory
+func appendAuditLine(fileName string, v interface{}) error {
+	dir, err := appDataDir()
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to serialize audit record: %v", err)
+	}
+
+	auditLogMu.Lock()
+	defer auditLogMu.Unlock()
+
+	file, err := os.OpenFile(filepath.Join(dir, fileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log: %v", err)
+	}
+	return file.Close()
+}
+
+// ProcessVideoWithAudit runs ProcessVideo and records who started it, why,
+// and how it ended. Processing does not start if the start record cannot be written.
+func (a *App) ProcessVideoWithAudit(request ProcessVideoRequest, userID string, reason string) ProcessVideoResponse {
+	if userID == "" {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "ValidationError",
+			Message:   "A user ID is required for audited processing.",
+		}
+	}
+
+	record := AuditRecord{
+		Event:       "started",
+		Timestamp:   time.Now(),
+		UserID:      userID,
+		Reason:      reason,
+		JobID:       generateID(),
+		Description: jobDescription(request),
+		InputPath:   request.InputPath,
+		OutputPath:  request.OutputPath,
+		Status:      "running",
+	}
+	if err := appendAuditRecord(record); err != nil {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "AuditError",
+			Message:   fmt.Sprintf("Could not write the audit trail, so processing was not started: %v", err),
+		}
+	}
+
+	response := a.ProcessVideo(request)
+
+	record.Event = "completed"
+	record.Timestamp = time.Now()
+	record.Status = response.Status
+	if response.OutputVideoPath != "" {
+		record.OutputPath = response.OutputVideoPath
+	}
+	if err := appendAuditRecord(record); err != nil {
+		a.logger.Error("failed to write audit completion record", map[string]interface{}{
+			"job_id": record.JobID,
+			"error":  err.Error(),
+		})
+	}
+
+	return response
+}
@@ -9,6 +9,10 @@ from datetime import datetime
 from typing import List, Dict, Any, Optional
 from tinydb import TinyDB
 
+# Version of the app <-> backend interface contract. Bump the major version for
+# changes the app must know about; the app refuses backends of another major.
+__version__ = "1.0.0"
+
 # Debug support
 try:
     import debugpy
@@ -32,6 +36,35 @@ except ImportError:
     )
     from timing_logic import TimingLogicProcessor, MotionState, FrameTimingDecision
 
+# The app caps the native thread pools through OMP_NUM_THREADS and related
+# variables; OpenCV keeps a pool of its own, so give it the same limit
+if os.environ.get("SUBKOMA_MAX_THREADS", "").isdigit():
+    cv2.setNumThreads(int(os.environ["SUBKOMA_MAX_THREADS"]))
+
+
+# ID of the JSON-RPC request being served in worker mode, None otherwise
+_worker_request_id = None
+
+
+def emit_progress(stage: str, frame: int, total_frames: int) -> None:
+    """Write a progress record to stdout as a single JSON line.
+
+    The Go frontend reads stdout line by line and forwards these records to the UI.
+    Any stdout line without "type": "progress" is treated as part of the final result.
+    In worker mode the record is wrapped in a "progress" notification instead.
+    """
+    record = {
+        "type": "progress",
+        "stage": stage,
+        "frame": frame,
+        "total_frames": total_frames
+    }
+    if _worker_request_id is not None:
+        record["id"] = _worker_request_id
+        write_rpc_message({"method": "progress", "params": record})
+        return
+    print(json.dumps(record), flush=True)
+
 
 def initialize_database(db_path: str = "analysis_results.json") -> TinyDB:
     """Initialize TinyDB database for storing analysis results."""
@@ -114,15 +147,51 @@ def extract_keypoints_from_frame(frame: np.ndarray, pose_detector) -> List[Keypo
     return keypoints
 
 
-def process_video_pipeline(input_path: str, output_path: str, config: Dict[str, Any]) -> AnalysisResult:
-    """Process video through the complete pipeline."""
+class GPUNotAvailableError(RuntimeError):
+    """The device the app pinned the backend to cannot be used."""
+
+
+def check_device() -> None:
+    """Verify that the device selected through SUBKOMA_DEVICE can be used.
+
+    The app sets SUBKOMA_DEVICE to cpu, cuda, metal or directml when the user
+    pins a device; when it is unset the backend picks one itself.
+    """
+    device = os.environ.get("SUBKOMA_DEVICE", "")
+    if device == "cuda":
+        try:
+            count = cv2.cuda.getCudaEnabledDeviceCount()
+        except (AttributeError, cv2.error):
+            count = 0
+        if count < 1:
+            raise GPUNotAvailableError(
+                "CUDA was selected but this OpenCV build cannot use a CUDA device. "
+                "Choose another device or CPU mode in the settings."
+            )
+    elif device == "metal" and sys.platform != "darwin":
+        raise GPUNotAvailableError("Metal is only available on macOS.")
+    elif device == "directml" and sys.platform != "win32":
+        raise GPUNotAvailableError("DirectML is only available on Windows.")
+
+
+def process_video_pipeline(input_path: str, output_path: str, config: Dict[str, Any],
+                           start_time: float = 0.0, end_time: float = 0.0) -> AnalysisResult:
+    """Process video through the complete pipeline.
+
+    start_time and end_time (in seconds) limit processing to a segment of the
+    video; an end_time of 0 means the end of the video. Frame indices and
+    timestamps in the result refer to the source video, not the segment.
+    """
     
     try:
         # Initialize MediaPipe pose detection
         mp_pose = mp.solutions.pose
+        # The app sets SUBKOMA_LOW_MEMORY when retrying after running out of
+        # memory; the lite model needs considerably less
+        low_memory = os.environ.get("SUBKOMA_LOW_MEMORY") == "1"
         pose_detector = mp_pose.Pose(
             static_image_mode=False,
-            model_complexity=1,
+            model_complexity=0 if low_memory else 1,
             enable_segmentation=False,
             min_detection_confidence=0.5,
             min_tracking_confidence=0.5
@@ -167,7 +236,22 @@ def process_video_pipeline(input_path: str, output_path: str, config: Dict[str,
         cap.release()
         raise ValueError(f"Invalid video dimensions: {width}x{height}. The video file may be corrupted.")
     
+    start_frame = int(round(start_time * fps))
+    end_frame = min(frame_count, int(round(end_time * fps))) if end_time > 0 else frame_count
+    if start_frame >= end_frame:
+        cap.release()
+        segment = f"{start_time:.3f}s to {end_time:.3f}s" if end_time > 0 else f"{start_time:.3f}s to the end"
+        raise ValueError(f"The segment from {segment} contains no frames of the {frame_count / fps:.3f}s video")
+    segment_frames = end_frame - start_frame
+
     print(f"Processing video: {frame_count} frames at {fps} FPS ({width}x{height})", file=sys.stderr)
+    if segment_frames < frame_count:
+        print(f"Processing frames {start_frame} to {end_frame - 1} only", file=sys.stderr)
+
+    # Seeking is not frame-accurate for every codec, so skip to the start by decoding
+    for _ in range(start_frame):
+        if not cap.grab():
+            break
     
     # Storage for frame analysis
     all_keypoints = []
@@ -177,13 +261,13 @@ def process_video_pipeline(input_path: str, output_path: str, config: Dict[str,
     frame_index = 0
     
     # Process each frame
-    while True:
+    while frame_index < segment_frames:
         ret, frame = cap.read()
         if not ret:
             break
         
         # Calculate timestamp
-        timestamp = frame_index / fps
+        timestamp = (start_frame + frame_index) / fps
         all_timestamps.append(timestamp)
         
         # Extract keypoints
@@ -234,11 +318,13 @@ def process_video_pipeline(input_path: str, output_path: str, config: Dict[str,
         
         # Progress reporting
         if frame_index % 30 == 0:
-            progress = (frame_index / frame_count) * 100
-            print(f"Progress: {progress:.1f}% ({frame_index}/{frame_count})", file=sys.stderr)
+            progress = (frame_index / segment_frames) * 100
+            print(f"Progress: {progress:.1f}% ({frame_index}/{segment_frames})", file=sys.stderr)
+            emit_progress("analysis", frame_index, segment_frames)
     
     cap.release()
     pose_detector.close()
+    emit_progress("analysis", frame_index, frame_index)
     
     print("Video analysis complete. Processing timing decisions...", file=sys.stderr)
     
@@ -253,32 +339,38 @@ def process_video_pipeline(input_path: str, output_path: str, config: Dict[str,
     frame_data = []
     for i, decision in enumerate(timing_decisions):
         frame_data.append(FrameData(
-            frame_index=i,
+            frame_index=start_frame + i,
             timestamp=all_timestamps[i],
             motion_intensity_score=motion_intensity_scores[i],
             motion_state=decision.motion_state.value,
             keypoints=all_keypoints[i] if config.get('save_keypoints', False) else None
         ))
     
+    # Record the segment with the parameters so the result can be interpreted
+    parameters = config
+    if start_time > 0 or end_time > 0:
+        parameters = dict(config, start_time=start_time, end_time=end_time)
+
     # Create analysis result
     analysis_result = AnalysisResult(
         source_video_path=input_path,
         output_video_path=output_path,
         analysis_timestamp=datetime.now().isoformat(),
-        parameters=config,
+        parameters=parameters,
         frame_data=frame_data
     )
     
     print("Analysis complete. Generating output video...", file=sys.stderr)
     
     # Generate the output video with timing decisions applied
-    generate_output_video(input_path, output_path, timing_decisions, fps)
+    generate_output_video(input_path, output_path, timing_decisions, fps, start_frame)
     
     print("Output video generation complete.", file=sys.stderr)
     return analysis_result
 
 
-def generate_output_video(input_path: str, output_path: str, timing_decisions: List[FrameTimingDecision], fps: float) -> None:
+def generate_output_video(input_path: str, output_path: str, timing_decisions: List[FrameTimingDecision], fps: float,
+                          start_frame: int = 0) -> None:
     """
     Generate the output video applying frame timing decisions.
     
@@ -287,6 +379,7 @@ def generate_output_video(input_path: str, output_path: str, timing_decisions: L
         output_path: Path where the output video will be saved
         timing_decisions: List of FrameTimingDecision objects
         fps: Original video frame rate
+        start_frame: Source frame the first timing decision applies to
     """
     # Open input video
     cap = cv2.VideoCapture(input_path)
@@ -315,6 +408,10 @@ def generate_output_video(input_path: str, output_path: str, timing_decisions: L
         raise RuntimeError(f"Could not create output video file: {output_path}")
     
     print(f"Generating output video: {width}x{height} at {fps} FPS", file=sys.stderr)
+
+    for _ in range(start_frame):
+        if not cap.grab():
+            break
     
     frame_index = 0
     total_output_frames = 0
@@ -341,25 +438,294 @@ def generate_output_video(input_path: str, output_path: str, timing_decisions: L
         if frame_index % 30 == 0:
             progress = (frame_index / len(timing_decisions)) * 100
             print(f"Video generation progress: {progress:.1f}% ({frame_index}/{len(timing_decisions)})", file=sys.stderr)
+            emit_progress("rendering", frame_index, len(timing_decisions))
     
     # Release everything
     cap.release()
     out.release()
+    emit_progress("rendering", frame_index, frame_index)
     
     print(f"Output video generated: {total_output_frames} frames written", file=sys.stderr)
 
 
+def export_frames(input_path: str, frames: List[Dict[str, Any]]) -> Dict[str, Any]:
+    """Write the requested frames of a video as image files.
+
+    Each entry of frames names a source frame ("frame_index") and the file to
+    write it to ("path"); the image format follows the file extension. The
+    video is read sequentially, since seeking is not frame-accurate for every
+    codec. Progress is reported with the "exporting" stage.
+    """
+    if not input_path:
+        raise ValueError("Input path cannot be empty")
+    if not os.path.exists(input_path):
+        raise FileNotFoundError(f"Input video file not found: {input_path}")
+
+    wanted: Dict[int, List[str]] = {}
+    for frame in frames:
+        wanted.setdefault(int(frame["frame_index"]), []).append(frame["path"])
+    if not wanted:
+        return {"status": "success", "exported": 0}
+
+    cap = cv2.VideoCapture(input_path)
+    if not cap.isOpened():
+        raise FileNotFoundError(f"Could not open input video file: {input_path}")
+
+    total = len(frames)
+    exported = 0
+    last_index = max(wanted)
+    frame_index = 0
+    try:
+        while frame_index <= last_index:
+            ret, image = cap.read()
+            if not ret:
+                break
+            for path in wanted.get(frame_index, []):
+                if not cv2.imwrite(path, image):
+                    raise PermissionError(f"Cannot write frame image: {path}")
+                exported += 1
+                if exported % 10 == 0:
+                    emit_progress("exporting", exported, total)
+            frame_index += 1
+    finally:
+        cap.release()
+
+    if exported < total:
+        raise ValueError(f"The video ended after {frame_index} frames; {total - exported} requested frames were not found")
+
+    emit_progress("exporting", exported, total)
+    return {"status": "success", "exported": exported}
+
+
+def run_job(input_path: str, output_path: str, config_json: str,
+            start_time: float = 0.0, end_time: float = 0.0) -> Dict[str, Any]:
+    """Validate the arguments, process the video and return the success result.
+
+    start_time and end_time limit processing to a segment, in seconds; an
+    end_time of 0 means the end of the video.
+
+    Failures are raised as exceptions; use error_result() to turn them into the
+    error JSON described in the interface contract.
+    """
+    # Enhanced input validation
+    if not input_path:
+        raise ValueError("Input path cannot be empty")
+
+    if not output_path:
+        raise ValueError("Output path cannot be empty")
+
+    if not config_json:
+        raise ValueError("Configuration cannot be empty")
+
+    if start_time < 0 or end_time < 0:
+        raise ValueError("Start and end times cannot be negative")
+    if end_time > 0 and end_time <= start_time:
+        raise ValueError(f"End time {end_time:.3f}s must be after start time {start_time:.3f}s")
+
+    check_device()
+
+    # Validate input file exists and is accessible
+    if not os.path.exists(input_path):
+        raise FileNotFoundError(f"Input video file not found: {input_path}")
+
+    if not os.access(input_path, os.R_OK):
+        raise PermissionError(f"Cannot read input video file: {input_path}")
+
+    # Validate output directory exists or can be created
+    output_dir = os.path.dirname(output_path)
+    if output_dir and not os.path.exists(output_dir):
+        try:
+            os.makedirs(output_dir, exist_ok=True)
+        except OSError as e:
+            raise PermissionError(f"Cannot create output directory {output_dir}: {e}")
+
+    # Check if output directory is writable
+    if output_dir and not os.access(output_dir, os.W_OK):
+        raise PermissionError(f"Cannot write to output directory: {output_dir}")
+
+    # Parse and validate config JSON
+    try:
+        config = json.loads(config_json)
+    except json.JSONDecodeError as e:
+        raise ValueError(f"Invalid JSON in config parameter: {e}")
+
+    # Validate config structure
+    required_keys = ['threshold_high', 'threshold_low', 'motion_weights']
+    for key in required_keys:
+        if key not in config:
+            print(f"Warning: Missing config key '{key}', using default value", file=sys.stderr)
+
+    # Validate motion weights if present
+    if 'motion_weights' in config:
+        weights = config['motion_weights']
+        if not isinstance(weights, dict):
+            raise ValueError("motion_weights must be a dictionary")
+
+        expected_weight_keys = ['displacement', 'velocity', 'acceleration', 'direction_change', 'pose_change']
+        for key in expected_weight_keys:
+            if key not in weights:
+                print(f"Warning: Missing motion weight '{key}', using default value", file=sys.stderr)
+            elif not isinstance(weights[key], (int, float)):
+                raise ValueError(f"Motion weight '{key}' must be a number")
+
+    print(f"Starting video processing: {input_path}", file=sys.stderr)
+    print(f"Output will be saved to: {output_path}", file=sys.stderr)
+
+    # Process the video
+    analysis_result = process_video_pipeline(input_path, output_path, config, start_time, end_time)
+
+    # Verify output file was created
+    if not os.path.exists(output_path):
+        raise RuntimeError(f"Output video file was not created: {output_path}")
+
+    # Save to database and get the ID
+    print("Saving analysis results to database...", file=sys.stderr)
+    try:
+        # Create database path in the same directory as input video
+        input_dir = os.path.dirname(input_path)
+        input_filename = os.path.splitext(os.path.basename(input_path))[0]
+        db_path = os.path.join(input_dir, f"{input_filename}_analysis.json")
+
+        database_id = save_analysis_result(analysis_result, db_path)
+        print(f"Analysis saved with database ID: {database_id}", file=sys.stderr)
+        print(f"Database saved to: {db_path}", file=sys.stderr)
+    except Exception as db_error:
+        print(f"Warning: Failed to save to database: {db_error}", file=sys.stderr)
+        database_id = None
+
+    # Return success result
+    return {
+        "status": "success",
+        "output_video_path": output_path,
+        "database_id": str(database_id) if database_id is not None else None,
+        "message": "Video processed successfully."
+    }
+
+
+def error_result(e: BaseException) -> Dict[str, Any]:
+    """Build the error JSON for an exception raised while processing."""
+    if isinstance(e, FileNotFoundError):
+        return {"status": "error", "error_type": "FileNotFoundError", "message": str(e)}
+    if isinstance(e, PermissionError):
+        return {"status": "error", "error_type": "PermissionError", "message": str(e)}
+    if isinstance(e, ValueError):
+        return {"status": "error", "error_type": "ValidationError", "message": str(e)}
+    if isinstance(e, ImportError):
+        return {
+            "status": "error",
+            "error_type": "DependencyError",
+            "message": f"Missing required Python package: {e}. Please install dependencies with: pip install -r requirements.txt"
+        }
+    if isinstance(e, GPUNotAvailableError):
+        return {"status": "error", "error_type": "GPUNotAvailableError", "message": str(e)}
+    if isinstance(e, MemoryError):
+        return {
+            "status": "error",
+            "error_type": "MemoryError",
+            "message": "Insufficient memory to process the video. Try with a smaller video file or close other applications."
+        }
+
+    # Enhanced error reporting for unexpected errors
+    error_type = type(e).__name__
+    error_message = str(e)
+
+    # Add context for common error types
+    if "cv2" in error_message.lower() or "opencv" in error_message.lower():
+        error_type = "VideoProcessingError"
+        error_message = f"Video processing failed: {error_message}. The video file may be corrupted or in an unsupported format."
+    elif "mediapipe" in error_message.lower():
+        error_type = "PoseDetectionError"
+        error_message = f"Pose detection failed: {error_message}. This may be due to an unsupported video format or corrupted frames."
+    elif "tinydb" in error_message.lower():
+        error_type = "DatabaseError"
+        error_message = f"Database operation failed: {error_message}. Check file permissions and disk space."
+
+    return {
+        "status": "error",
+        "error_type": error_type,
+        "message": error_message
+    }
+
+
+def write_rpc_message(message: Dict[str, Any]) -> None:
+    """Write one JSON-RPC message to stdout as a single line."""
+    message["jsonrpc"] = "2.0"
+    print(json.dumps(message), flush=True)
+
+
+def run_worker() -> None:
+    """Serve requests read from stdin as line-delimited JSON-RPC 2.0 until EOF.
+
+    Methods:
+      ping          -> {"status": "ok"}
+      process_video -> the same result object the CLI prints, including errors
+      export_frames -> {"status": "success", "exported": n} or an error object
+      shutdown      -> {"status": "ok"}, then the worker exits
+    Progress records are sent as "progress" notifications carrying the request ID.
+    """
+    global _worker_request_id
+
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+
+        try:
+            request = json.loads(line)
+        except json.JSONDecodeError as e:
+            write_rpc_message({"id": None, "error": {"code": -32700, "message": f"Parse error: {e}"}})
+            continue
+
+        request_id = request.get("id")
+        method = request.get("method")
+        params = request.get("params") or {}
+
+        if method == "ping":
+            write_rpc_message({"id": request_id, "result": {"status": "ok"}})
+        elif method == "process_video":
+            _worker_request_id = request_id
+            try:
+                result = run_job(params.get("input_path", ""), params.get("output_path", ""), params.get("config", ""),
+                                 float(params.get("start_time") or 0), float(params.get("end_time") or 0))
+            except Exception as e:
+                result = error_result(e)
+            finally:
+                _worker_request_id = None
+            write_rpc_message({"id": request_id, "result": result})
+        elif method == "export_frames":
+            _worker_request_id = request_id
+            try:
+                result = export_frames(params.get("input_path", ""), params.get("frames") or [])
+            except Exception as e:
+                result = error_result(e)
+            finally:
+                _worker_request_id = None
+            write_rpc_message({"id": request_id, "result": result})
+        elif method == "shutdown":
+            write_rpc_message({"id": request_id, "result": {"status": "ok"}})
+            return
+        else:
+            write_rpc_message({"id": request_id, "error": {"code": -32601, "message": f"Method not found: {method}"}})
+
+
 def main():
     parser = argparse.ArgumentParser(description='Process a video to identify key frames for animation.')
-    parser.add_argument('--input', type=str, required=True, help='The absolute path to the source video file.')
-    parser.add_argument('--output', type=str, required=True, help='The absolute path where the processed video will be saved.')
-    parser.add_argument('--config', type=str, required=True, help='A JSON string containing analysis parameters.')
+    parser.add_argument('--input', type=str, help='The absolute path to the source video file.')
+    parser.add_argument('--output', type=str, help='The absolute path where the processed video will be saved.')
+    parser.add_argument('--config', type=str, help='A JSON string containing analysis parameters.')
+    parser.add_argument('--start', type=float, default=0.0, help='Start of the segment to process, in seconds (default: 0)')
+    parser.add_argument('--end', type=float, default=0.0, help='End of the segment to process, in seconds (default: 0, the end of the video)')
+    parser.add_argument('--version', action='version', version=__version__, help='Print the backend version and exit')
+    parser.add_argument('--worker', action='store_true', help='Serve JSON-RPC requests on stdin/stdout instead of processing one video')
+    parser.add_argument('--export-frames', type=str, metavar='SPEC', help='Export video frames as images, as described by a JSON file with "input_path" and "frames"')
     parser.add_argument('--debug', action='store_true', help='Enable debug mode - starts debugpy server')
     parser.add_argument('--debug-port', type=int, default=5678, help='Port for debug server (default: 5678)')
     parser.add_argument('--debug-wait', action='store_true', help='Wait for debugger to attach before starting')
 
     try:
         args = parser.parse_args()
+        if not args.worker and not args.export_frames and (args.input is None or args.output is None or args.config is None):
+            parser.error("--input, --output and --config are required unless --worker or --export-frames is given")
         
         # Initialize debug server if requested
         if args.debug and DEBUGPY_AVAILABLE:
@@ -377,168 +743,34 @@ def main():
                 print(f"Warning: Failed to start debug server: {debug_error}", file=sys.stderr)
         elif args.debug and not DEBUGPY_AVAILABLE:
             print("Warning: debugpy not available. Install with: pip install debugpy", file=sys.stderr)
-        
-        # Enhanced input validation
-        if not args.input:
-            raise ValueError("Input path cannot be empty")
-        
-        if not args.output:
-            raise ValueError("Output path cannot be empty")
-            
-        if not args.config:
-            raise ValueError("Configuration cannot be empty")
-        
-        # Validate input file exists and is accessible
-        if not os.path.exists(args.input):
-            raise FileNotFoundError(f"Input video file not found: {args.input}")
-        
-        if not os.access(args.input, os.R_OK):
-            raise PermissionError(f"Cannot read input video file: {args.input}")
-        
-        # Validate output directory exists or can be created
-        output_dir = os.path.dirname(args.output)
-        if output_dir and not os.path.exists(output_dir):
-            try:
-                os.makedirs(output_dir, exist_ok=True)
-            except OSError as e:
-                raise PermissionError(f"Cannot create output directory {output_dir}: {e}")
-        
-        # Check if output directory is writable
-        if output_dir and not os.access(output_dir, os.W_OK):
-            raise PermissionError(f"Cannot write to output directory: {output_dir}")
-        
-        # Parse and validate config JSON
-        try:
-            config = json.loads(args.config)
-        except json.JSONDecodeError as e:
-            raise ValueError(f"Invalid JSON in config parameter: {e}")
-        
-        # Validate config structure
-        required_keys = ['threshold_high', 'threshold_low', 'motion_weights']
-        for key in required_keys:
-            if key not in config:
-                print(f"Warning: Missing config key '{key}', using default value", file=sys.stderr)
-        
-        # Validate motion weights if present
-        if 'motion_weights' in config:
-            weights = config['motion_weights']
-            if not isinstance(weights, dict):
-                raise ValueError("motion_weights must be a dictionary")
-            
-            expected_weight_keys = ['displacement', 'velocity', 'acceleration', 'direction_change', 'pose_change']
-            for key in expected_weight_keys:
-                if key not in weights:
-                    print(f"Warning: Missing motion weight '{key}', using default value", file=sys.stderr)
-                elif not isinstance(weights[key], (int, float)):
-                    raise ValueError(f"Motion weight '{key}' must be a number")
-        
-        print(f"Starting video processing: {args.input}", file=sys.stderr)
-        print(f"Output will be saved to: {args.output}", file=sys.stderr)
-        
-        # Process the video
-        analysis_result = process_video_pipeline(args.input, args.output, config)
-        
-        # Verify output file was created
-        if not os.path.exists(args.output):
-            raise RuntimeError(f"Output video file was not created: {args.output}")
-        
-        # Save to database and get the ID
-        print("Saving analysis results to database...", file=sys.stderr)
-        try:
-            # Create database path in the same directory as input video
-            input_dir = os.path.dirname(args.input)
-            input_filename = os.path.splitext(os.path.basename(args.input))[0]
-            db_path = os.path.join(input_dir, f"{input_filename}_analysis.json")
-            
-            database_id = save_analysis_result(analysis_result, db_path)
-            print(f"Analysis saved with database ID: {database_id}", file=sys.stderr)
-            print(f"Database saved to: {db_path}", file=sys.stderr)
-        except Exception as db_error:
-            print(f"Warning: Failed to save to database: {db_error}", file=sys.stderr)
-            database_id = None
-        
-        # Return success result
-        result = {
-            "status": "success",
-            "output_video_path": args.output,
-            "database_id": str(database_id) if database_id is not None else None,
-            "message": "Video processed successfully."
-        }
+
+        if args.worker:
+            run_worker()
+            sys.exit(0)
+
+        if args.export_frames:
+            with open(args.export_frames, encoding='utf-8') as f:
+                spec = json.load(f)
+            result = export_frames(spec.get("input_path", ""), spec.get("frames") or [])
+            print(json.dumps(result))
+            sys.exit(0)
+
+        result = run_job(args.input, args.output, args.config, args.start, args.end)
         print(json.dumps(result))
         sys.exit(0)
         
     except SystemExit as e:
         # This is to catch the exit from argparse, which is not an error in this context
         if e.code != 0:
-            error_result = {
+            error_result_json = {
                 "status": "error",
                 "error_type": "ArgumentError",
                 "message": "Invalid command line arguments. Please check your input parameters."
             }
-            print(json.dumps(error_result), file=sys.stderr)
+            print(json.dumps(error_result_json), file=sys.stderr)
             sys.exit(e.code)
-    except FileNotFoundError as e:
-        error_result = {
-            "status": "error",
-            "error_type": "FileNotFoundError",
-            "message": str(e)
-        }
-        print(json.dumps(error_result), file=sys.stderr)
-        sys.exit(1)
-    except PermissionError as e:
-        error_result = {
-            "status": "error",
-            "error_type": "PermissionError",
-            "message": str(e)
-        }
-        print(json.dumps(error_result), file=sys.stderr)
-        sys.exit(1)
-    except ValueError as e:
-        error_result = {
-            "status": "error",
-            "error_type": "ValidationError",
-            "message": str(e)
-        }
-        print(json.dumps(error_result), file=sys.stderr)
-        sys.exit(1)
-    except ImportError as e:
-        error_result = {
-            "status": "error",
-            "error_type": "DependencyError",
-            "message": f"Missing required Python package: {e}. Please install dependencies with: pip install -r requirements.txt"
-        }
-        print(json.dumps(error_result), file=sys.stderr)
-        sys.exit(1)
-    except MemoryError as e:
-        error_result = {
-            "status": "error",
-            "error_type": "MemoryError",
-            "message": "Insufficient memory to process the video. Try with a smaller video file or close other applications."
-        }
-        print(json.dumps(error_result), file=sys.stderr)
-        sys.exit(1)
     except Exception as e:
-        # Enhanced error reporting for unexpected errors
-        error_type = type(e).__name__
-        error_message = str(e)
-        
-        # Add context for common error types
-        if "cv2" in error_message.lower() or "opencv" in error_message.lower():
-            error_type = "VideoProcessingError"
-            error_message = f"Video processing failed: {error_message}. The video file may be corrupted or in an unsupported format."
-        elif "mediapipe" in error_message.lower():
-            error_type = "PoseDetectionError"
-            error_message = f"Pose detection failed: {error_message}. This may be due to an unsupported video format or corrupted frames."
-        elif "tinydb" in error_message.lower():
-            error_type = "DatabaseError"
-            error_message = f"Database operation failed: {error_message}. Check file permissions and disk space."
-        
-        error_result = {
-            "status": "error",
-            "error_type": error_type,
-            "message": error_message
-        }
-        print(json.dumps(error_result), file=sys.stderr)
+        print(json.dumps(error_result(e)), file=sys.stderr)
         sys.exit(1)
 
 
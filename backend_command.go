@@ -0,0 +1,98 @@
+package main
+
+import "strconv"
+
+// BackendCommandBuilder assembles the command-line arguments for the Python
+// processing script, following the contract in
+// specs/001-implementation-presentation-md/contracts/python_interface.md
+type BackendCommandBuilder struct {
+	scriptPath string
+	input      string
+	output     string
+	config     string
+	debug      bool
+	debugWait  bool
+	debugPort  string
+	worker     bool
+	start      float64
+	end        float64
+}
+
+// NewBackendCommandBuilder starts a command for the given script
+func NewBackendCommandBuilder(scriptPath string) *BackendCommandBuilder {
+	return &BackendCommandBuilder{scriptPath: scriptPath}
+}
+
+// WithInput sets the source video path
+func (b *BackendCommandBuilder) WithInput(path string) *BackendCommandBuilder {
+	b.input = path
+	return b
+}
+
+// WithOutput sets the processed video path
+func (b *BackendCommandBuilder) WithOutput(path string) *BackendCommandBuilder {
+	b.output = path
+	return b
+}
+
+// WithConfig sets the JSON analysis configuration
+func (b *BackendCommandBuilder) WithConfig(json string) *BackendCommandBuilder {
+	b.config = json
+	return b
+}
+
+// WithTrim limits processing to the segment from start to end, in seconds.
+// Zero values omit the flags, processing from the beginning or to the end.
+func (b *BackendCommandBuilder) WithTrim(start, end float64) *BackendCommandBuilder {
+	b.start = start
+	b.end = end
+	return b
+}
+
+// WithDebug enables the debugpy server, optionally waiting for a debugger
+// to attach. An empty port keeps the backend default.
+func (b *BackendCommandBuilder) WithDebug(wait bool, port string) *BackendCommandBuilder {
+	b.debug = true
+	b.debugWait = wait
+	b.debugPort = port
+	return b
+}
+
+// WithWorker runs the script as a persistent JSON-RPC worker. Input, output
+// and config are sent per request and are omitted from the arguments.
+func (b *BackendCommandBuilder) WithWorker() *BackendCommandBuilder {
+	b.worker = true
+	return b
+}
+
+// Build returns the script path followed by its arguments
+func (b *BackendCommandBuilder) Build() []string {
+	args := []string{b.scriptPath}
+	if b.worker {
+		args = append(args, "--worker")
+	} else {
+		args = append(args,
+			"--input", b.input,
+			"--output", b.output,
+			"--config", b.config,
+		)
+		if b.start > 0 {
+			args = append(args, "--start", strconv.FormatFloat(b.start, 'f', -1, 64))
+		}
+		if b.end > 0 {
+			args = append(args, "--end", strconv.FormatFloat(b.end, 'f', -1, 64))
+		}
+	}
+
+	if b.debug {
+		args = append(args, "--debug")
+		if b.debugWait {
+			args = append(args, "--debug-wait")
+		}
+		if b.debugPort != "" {
+			args = append(args, "--debug-port", b.debugPort)
+		}
+	}
+
+	return args
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackendCommandBuilderBuild(t *testing.T) {
+	base := func() *BackendCommandBuilder {
+		return NewBackendCommandBuilder("process_video.py").
+			WithInput("in.mp4").
+			WithOutput("out.mp4").
+			WithConfig(`{"mode":"x"}`)
+	}
+	baseArgs := []string{"process_video.py", "--input", "in.mp4", "--output", "out.mp4", "--config", `{"mode":"x"}`}
+	with := func(extra ...string) []string {
+		return append(append([]string{}, baseArgs...), extra...)
+	}
+
+	tests := []struct {
+		name    string
+		builder *BackendCommandBuilder
+		want    []string
+	}{
+		{
+			name:    "base arguments",
+			builder: base(),
+			want:    baseArgs,
+		},
+		{
+			name:    "debug without wait",
+			builder: base().WithDebug(false, ""),
+			want:    with("--debug"),
+		},
+		{
+			name:    "debug with wait",
+			builder: base().WithDebug(true, ""),
+			want:    with("--debug", "--debug-wait"),
+		},
+		{
+			name:    "debug port",
+			builder: base().WithDebug(false, "5679"),
+			want:    with("--debug", "--debug-port", "5679"),
+		},
+		{
+			name:    "debug with wait and port",
+			builder: base().WithDebug(true, "5679"),
+			want:    with("--debug", "--debug-wait", "--debug-port", "5679"),
+		},
+		{
+			name:    "worker omits per-request arguments",
+			builder: base().WithWorker(),
+			want:    []string{"process_video.py", "--worker"},
+		},
+		{
+			name:    "worker with debug",
+			builder: base().WithWorker().WithDebug(true, "5679"),
+			want:    []string{"process_video.py", "--worker", "--debug", "--debug-wait", "--debug-port", "5679"},
+		},
+		{
+			name:    "trim start and end",
+			builder: base().WithTrim(1.5, 10),
+			want:    with("--start", "1.5", "--end", "10"),
+		},
+		{
+			name:    "trim start only",
+			builder: base().WithTrim(2.25, 0),
+			want:    with("--start", "2.25"),
+		},
+		{
+			name:    "trim end only",
+			builder: base().WithTrim(0, 7),
+			want:    with("--end", "7"),
+		},
+		{
+			name:    "worker ignores trim",
+			builder: base().WithWorker().WithTrim(1, 2),
+			want:    []string{"process_video.py", "--worker"},
+		},
+		{
+			name:    "trim before debug",
+			builder: base().WithTrim(1, 2).WithDebug(false, ""),
+			want:    with("--start", "1", "--end", "2", "--debug"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.builder.Build(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// threadLimitVars are the variables that cap the thread pools of the
+// numerical libraries the backend uses. SUBKOMA_MAX_THREADS is read by the
+// backend itself to limit OpenCV.
+var threadLimitVars = []string{
+	"SUBKOMA_MAX_THREADS",
+	"OMP_NUM_THREADS",
+	"OPENBLAS_NUM_THREADS",
+	"MKL_NUM_THREADS",
+	"NUMEXPR_NUM_THREADS",
+	"VECLIB_MAXIMUM_THREADS",
+	"TF_NUM_INTRAOP_THREADS",
+	"TF_NUM_INTEROP_THREADS",
+}
+
+// validateBackendEnv checks that the extra environment variables can be passed to a subprocess
+func validateBackendEnv(env map[string]string) error {
+	for key := range env {
+		if key == "" || strings.ContainsAny(key, "=\x00") {
+			return fmt.Errorf("invalid environment variable name %q", key)
+		}
+	}
+	return nil
+}
+
+// backendEnv builds the environment of the backend subprocess: the inherited
+// process environment, the thread limits and device from the settings, and the
+// variables injected through the request, which win over all of them
+func (a *App) backendEnv(request ProcessVideoRequest) []string {
+	env := os.Environ()
+
+	settings := a.GetSettings()
+	env = append(env, deviceEnv(settings.Device)...)
+	if maxThreads := settings.MaxThreads; maxThreads > 0 {
+		for _, key := range threadLimitVars {
+			env = append(env, key+"="+strconv.Itoa(maxThreads))
+		}
+	}
+
+	keys := make([]string, 0, len(request.Env))
+	for key := range request.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+request.Env[key])
+	}
+
+	return env
+}
+
+// GetSubprocessEnvironmentSnapshot returns the environment the backend process
+// would see for the given request, without starting it
+func (a *App) GetSubprocessEnvironmentSnapshot(request ProcessVideoRequest) (map[string]string, error) {
+	if err := validateBackendEnv(request.Env); err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]string)
+	for _, entry := range a.backendEnv(request) {
+		key, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		// Later entries win, matching how the subprocess resolves duplicates
+		snapshot[key] = value
+	}
+	return snapshot, nil
+}
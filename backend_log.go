@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackendLogLine is the payload of "backend:log" events
+type BackendLogLine struct {
+	JobID string    `json:"job_id,omitempty"`
+	Level string    `json:"level"` // DEBUG, INFO, WARN or ERROR
+	Line  string    `json:"line"`
+	Time  time.Time `json:"time"`
+}
+
+// backendLogLevel infers the level of a backend stderr line from its prefix.
+// Tracebacks and the error JSON written on failure count as errors.
+func backendLogLevel(line string) string {
+	trimmed := strings.TrimSpace(line)
+	upper := strings.ToUpper(trimmed)
+	switch {
+	case strings.HasPrefix(upper, "ERROR"), strings.HasPrefix(upper, "CRITICAL"),
+		strings.HasPrefix(trimmed, "Traceback"), strings.HasPrefix(upper, "FATAL"):
+		return "ERROR"
+	case strings.HasPrefix(upper, "WARN"):
+		return "WARN"
+	case strings.HasPrefix(upper, "DEBUG"):
+		return "DEBUG"
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		var record struct {
+			Status string `json:"status"`
+		}
+		if json.Unmarshal([]byte(trimmed), &record) == nil && record.Status == "error" {
+			return "ERROR"
+		}
+	}
+	return "INFO"
+}
+
+// streamBackendLog emits each line read from r as a "backend:log" event until
+// EOF. jobID is called per line so a long-lived worker can attribute output to
+// whichever job it is serving. Lines are also copied to capture when it is not nil.
+func (a *App) streamBackendLog(r io.Reader, jobID func() string, capture *lockedBuffer) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if capture != nil {
+			capture.WriteLine(line)
+		}
+		a.emit("backend:log", BackendLogLine{
+			JobID: jobID(),
+			Level: backendLogLevel(line),
+			Line:  line,
+			Time:  time.Now(),
+		})
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// lockedBuffer collects lines written from one goroutine and read from another
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// WriteLine appends line and a newline
+func (b *lockedBuffer) WriteLine(line string) {
+	b.mu.Lock()
+	b.buf.WriteString(line)
+	b.buf.WriteByte('\n')
+	b.mu.Unlock()
+}
+
+// Reset discards the collected lines
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	b.buf.Reset()
+	b.mu.Unlock()
+}
+
+// Bytes returns a copy of the collected lines
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+// lastJSONLine returns the last line of output that looks like a JSON object,
+// which is where the backend writes its error record after any log output
+func lastJSONLine(output []byte) []byte {
+	lines := bytes.Split(bytes.TrimSpace(output), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := bytes.TrimSpace(lines[i])
+		if bytes.HasPrefix(line, []byte("{")) {
+			return line
+		}
+	}
+	return nil
+}
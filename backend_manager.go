@@ -0,0 +1,642 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// workerHealthInterval is how often an idle worker is pinged
+	workerHealthInterval = 30 * time.Second
+	// workerPingTimeout is how long a ping may take before the worker is restarted
+	workerPingTimeout = 5 * time.Second
+	// workerRestartDelay is the pause before a crashed worker is relaunched
+	workerRestartDelay = time.Second
+	// maxWorkerRestarts is how many consecutive short-lived crashes are tolerated
+	// before the manager gives up and requests fall back to one process per job
+	maxWorkerRestarts = 5
+	// workerStableAfter is how long a worker must run before its crash counter resets
+	workerStableAfter = time.Minute
+)
+
+// errWorkerNotRunning is returned for calls made while no worker process is
+// alive. The request never reached a worker, so it can be run another way.
+var errWorkerNotRunning = errors.New("backend worker is not running")
+
+// errWorkerExited is returned for calls whose worker died before answering
+var errWorkerExited = errors.New("backend worker exited")
+
+// rpcError is a JSON-RPC 2.0 error object
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcMessage is any JSON-RPC 2.0 message exchanged with the worker
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *string         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// workerProgress is the params object of a "progress" notification
+type workerProgress struct {
+	backendProgress
+	ID string `json:"id"`
+}
+
+// BackendStatus describes the persistent backend worker
+type BackendStatus struct {
+	Running         bool      `json:"running"`
+	PID             int       `json:"pid,omitempty"`
+	Restarts        int       `json:"restarts"`
+	StartedAt       time.Time `json:"started_at"`
+	LastHealthCheck time.Time `json:"last_health_check"`
+	Healthy         bool      `json:"healthy"`
+	LastError       string    `json:"last_error,omitempty"`
+}
+
+// BackendManager keeps one Python worker process alive and exchanges
+// line-delimited JSON-RPC messages with it over stdin/stdout. The worker
+// handles one request at a time, so calls are serialized.
+type BackendManager struct {
+	app *App
+	dir string // folder containing the backend script
+
+	callMu sync.Mutex // held while the worker is reserved for a job or health check
+
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	pending   map[string]chan rpcMessage
+	trackers  map[string]*progressTracker
+	status    BackendStatus
+	crashes   int
+	killed    *exec.Cmd // process terminated on purpose, not counted as a crash
+	stopped   bool
+	stopCh    chan struct{}
+	exitedCh  chan struct{} // closed when the current process exits
+	requestID int
+	jobID     string // job being served, for attributing log output
+}
+
+// NewBackendManager creates a manager for the backend script in dir
+func NewBackendManager(app *App, dir string) *BackendManager {
+	return &BackendManager{
+		app:      app,
+		dir:      dir,
+		pending:  make(map[string]chan rpcMessage),
+		trackers: make(map[string]*progressTracker),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start launches the worker and begins health checks
+func (m *BackendManager) Start() error {
+	if err := m.launch(); err != nil {
+		return err
+	}
+	go m.healthLoop()
+	return nil
+}
+
+// launch starts a new worker process
+func (m *BackendManager) launch() error {
+	python, args := m.app.pythonCommand(NewBackendCommandBuilder(backendScriptName).WithWorker().Build()...)
+	cmd := exec.Command(python, args...)
+	cmd.Dir = m.dir
+	cmd.Env = m.app.backendEnv(ProcessVideoRequest{})
+	setProcessGroup(cmd)
+	if m.app.GetSettings().LowPriority {
+		setLowPriority(cmd)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to attach to worker input: %v", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to attach to worker output: %v", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to attach to worker output: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start backend worker: %v", err)
+	}
+
+	exited := make(chan struct{})
+	m.mu.Lock()
+	m.cmd = cmd
+	m.stdin = stdin
+	m.exitedCh = exited
+	m.status.Running = true
+	m.status.Healthy = true
+	m.status.PID = cmd.Process.Pid
+	m.status.StartedAt = time.Now()
+	m.mu.Unlock()
+
+	m.app.logger.Info("backend worker started", map[string]interface{}{"pid": cmd.Process.Pid, "dir": cmd.Dir})
+
+	go m.readLoop(stdout)
+	go m.app.streamBackendLog(stderr, m.currentJobID, nil)
+	go m.waitLoop(cmd, exited)
+	return nil
+}
+
+// currentJobID returns the job the worker is serving, or an empty string when idle
+func (m *BackendManager) currentJobID() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jobID
+}
+
+// readLoop dispatches responses and progress notifications from the worker
+func (m *BackendManager) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var msg rpcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			m.app.logger.Warn("ignoring malformed worker output", map[string]interface{}{"line": scanner.Text()})
+			continue
+		}
+
+		if msg.Method == "progress" {
+			var progress workerProgress
+			if json.Unmarshal(msg.Params, &progress) == nil {
+				m.mu.Lock()
+				tracker := m.trackers[progress.ID]
+				m.mu.Unlock()
+				if tracker != nil {
+					tracker.handle(progress.backendProgress)
+				}
+			}
+			continue
+		}
+
+		if msg.ID == nil {
+			continue
+		}
+		m.mu.Lock()
+		ch := m.pending[*msg.ID]
+		delete(m.pending, *msg.ID)
+		m.mu.Unlock()
+		if ch != nil {
+			ch <- msg
+		}
+	}
+	_, _ = io.Copy(io.Discard, stdout)
+}
+
+// waitLoop reaps the worker and restarts it unless the manager was stopped
+func (m *BackendManager) waitLoop(cmd *exec.Cmd, exited chan struct{}) {
+	err := cmd.Wait()
+	close(exited)
+
+	m.mu.Lock()
+	m.status.Running = false
+	m.status.Healthy = false
+	m.status.PID = 0
+	if err != nil {
+		m.status.LastError = err.Error()
+	}
+	killed := m.killed == cmd
+	if killed {
+		m.killed = nil
+	} else {
+		if time.Since(m.status.StartedAt) >= workerStableAfter {
+			m.crashes = 0
+		}
+		m.crashes++
+	}
+	crashes := m.crashes
+	stopped := m.stopped
+	m.mu.Unlock()
+
+	if stopped {
+		return
+	}
+
+	if killed {
+		m.app.logger.Info("backend worker terminated, restarting", nil)
+	} else {
+		fields := map[string]interface{}{"crashes": crashes}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		m.app.logger.Warn("backend worker exited", fields)
+		m.app.emit("backend:crashed", m.Status())
+	}
+
+	if crashes > maxWorkerRestarts {
+		m.app.logger.Error("backend worker keeps crashing, giving up", map[string]interface{}{"crashes": crashes})
+		return
+	}
+
+	select {
+	case <-m.stopCh:
+		return
+	case <-time.After(workerRestartDelay):
+	}
+
+	if err := m.launch(); err != nil {
+		m.app.logger.Error("failed to restart backend worker", map[string]interface{}{"error": err.Error()})
+		m.mu.Lock()
+		m.status.LastError = err.Error()
+		m.mu.Unlock()
+		return
+	}
+
+	m.mu.Lock()
+	m.status.Restarts++
+	m.mu.Unlock()
+	m.app.emit("backend:restarted", m.Status())
+}
+
+// healthLoop pings the worker while it is idle and kills it if it stops answering
+func (m *BackendManager) healthLoop() {
+	ticker := time.NewTicker(workerHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		// A busy worker cannot answer until its current job finishes
+		if !m.callMu.TryLock() {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), workerPingTimeout)
+		_, err := m.call(ctx, "ping", nil, nil)
+		cancel()
+		m.callMu.Unlock()
+
+		if errors.Is(err, errWorkerNotRunning) {
+			continue
+		}
+
+		m.mu.Lock()
+		m.status.LastHealthCheck = time.Now()
+		m.status.Healthy = err == nil
+		cmd := m.cmd
+		m.mu.Unlock()
+
+		if err != nil {
+			m.app.logger.Warn("backend worker failed health check", map[string]interface{}{"error": err.Error()})
+			_ = m.terminate(cmd)
+		}
+	}
+}
+
+// terminate kills a worker process on purpose, e.g. to abandon a cancelled
+// or timed-out job, so its exit is not counted as a crash
+func (m *BackendManager) terminate(cmd *exec.Cmd) error {
+	m.mu.Lock()
+	if m.cmd == cmd {
+		m.killed = cmd
+	}
+	m.mu.Unlock()
+	return terminateProcessTree(cmd)
+}
+
+// Running reports whether a worker process is alive
+func (m *BackendManager) Running() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status.Running
+}
+
+// Status returns a snapshot of the worker state
+func (m *BackendManager) Status() BackendStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Stop asks the worker to exit and stops restarting it
+func (m *BackendManager) Stop() {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return
+	}
+	m.stopped = true
+	close(m.stopCh)
+	cmd := m.cmd
+	running := m.status.Running
+	exited := m.exitedCh
+	m.mu.Unlock()
+
+	if !running {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), workerPingTimeout)
+	defer cancel()
+	if _, err := m.call(ctx, "shutdown", nil, nil); err == nil {
+		select {
+		case <-exited:
+			return
+		case <-ctx.Done():
+		}
+	}
+	_ = terminateProcessTree(cmd)
+}
+
+// call sends a request and waits for its response. Progress notifications
+// for the request are passed to tracker when it is not nil.
+func (m *BackendManager) call(ctx context.Context, method string, params interface{}, tracker *progressTracker) (json.RawMessage, error) {
+	m.mu.Lock()
+	if !m.status.Running {
+		m.mu.Unlock()
+		return nil, errWorkerNotRunning
+	}
+	m.requestID++
+	id := strconv.Itoa(m.requestID)
+	ch := make(chan rpcMessage, 1)
+	m.pending[id] = ch
+	if tracker != nil {
+		m.trackers[id] = tracker
+	}
+	stdin := m.stdin
+	exited := m.exitedCh
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.pending, id)
+		delete(m.trackers, id)
+		m.mu.Unlock()
+	}()
+
+	request := rpcMessage{JSONRPC: "2.0", ID: &id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s request: %v", method, err)
+		}
+		request.Params = data
+	}
+	line, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %v", method, err)
+	}
+	if _, err := stdin.Write(append(line, '\n')); err != nil {
+		// The worker exited before it could read the request
+		return nil, fmt.Errorf("%w: failed to send %s request: %v", errWorkerNotRunning, method, err)
+	}
+
+	select {
+	case msg := <-ch:
+		if msg.Error != nil {
+			return nil, fmt.Errorf("backend worker rejected %s: %s", method, msg.Error.Message)
+		}
+		return msg.Result, nil
+	case <-exited:
+		return nil, fmt.Errorf("%w while handling %s", errWorkerExited, method)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// tryReserve claims the worker for one ProcessVideo call. It returns false
+// while another job is using it.
+func (m *BackendManager) tryReserve() bool {
+	return m.callMu.TryLock()
+}
+
+// ProcessVideo runs a request on the worker, which must have been reserved
+// with tryReserve; the reservation is released when the call returns. It
+// returns the result object as stdout, or the error object as stderr together
+// with a non-nil error, so the caller can handle both the same way as output
+// from a one-shot backend process.
+func (m *BackendManager) ProcessVideo(ctx context.Context, request ProcessVideoRequest, job *runningJob) ([]byte, []byte, error) {
+	defer m.callMu.Unlock()
+
+	m.mu.Lock()
+	cmd := m.cmd
+	m.jobID = request.JobID
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.jobID = ""
+		m.mu.Unlock()
+	}()
+	// Cancelling the job stops the worker, which is then restarted
+	m.app.attachJobProcess(job, func() error { return m.terminate(cmd) })
+
+	params := map[string]interface{}{
+		"input_path":  request.InputPath,
+		"output_path": request.OutputPath,
+		"config":      request.Config,
+		"start_time":  request.StartTime,
+		"end_time":    request.EndTime,
+	}
+	tracker := &progressTracker{app: m.app, jobID: request.JobID, inputPath: request.InputPath}
+
+	result, err := m.call(ctx, "process_video", params, tracker)
+	if err != nil {
+		if ctx.Err() != nil {
+			// The worker is still busy with the abandoned job
+			_ = m.terminate(cmd)
+		}
+		return nil, nil, err
+	}
+
+	var status struct {
+		Status    string `json:"status"`
+		ErrorType string `json:"error_type"`
+	}
+	if err := json.Unmarshal(result, &status); err == nil && status.Status == "error" {
+		return nil, result, fmt.Errorf("backend reported %s", status.ErrorType)
+	}
+	return result, nil, nil
+}
+
+// ExportFrames runs an export_frames request on the worker, which must have
+// been reserved with tryReserve; the reservation is released when the call
+// returns. The result object is returned as is, including backend errors.
+func (m *BackendManager) ExportFrames(ctx context.Context, params interface{}, tracker *progressTracker) (json.RawMessage, error) {
+	defer m.callMu.Unlock()
+
+	m.mu.Lock()
+	cmd := m.cmd
+	m.mu.Unlock()
+
+	result, err := m.call(ctx, "export_frames", params, tracker)
+	if err != nil && ctx.Err() != nil {
+		_ = m.terminate(cmd)
+	}
+	return result, err
+}
+
+// GetBackendStatus returns the state of the persistent backend worker
+func (a *App) GetBackendStatus() BackendStatus {
+	a.mu.RLock()
+	backend := a.backend
+	a.mu.RUnlock()
+
+	if backend == nil {
+		return BackendStatus{}
+	}
+	return backend.Status()
+}
+
+// startBackendWorker launches the persistent worker for the current working
+// directory. Requests fall back to one process per job if it fails to start.
+func (a *App) startBackendWorker() {
+	if !a.config.UsePersistentBackend {
+		return
+	}
+	dir := a.backendDir()
+	if dir == "" {
+		return
+	}
+
+	backend := NewBackendManager(a, dir)
+	if err := backend.Start(); err != nil {
+		a.logger.Warn("backend worker unavailable, using one process per job", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	a.mu.Lock()
+	a.backend = backend
+	a.mu.Unlock()
+}
+
+// restartBackendWorker stops the persistent worker and launches a new one for
+// the current backend directory
+func (a *App) restartBackendWorker() {
+	a.mu.Lock()
+	backend := a.backend
+	a.backend = nil
+	a.mu.Unlock()
+
+	if backend != nil {
+		backend.Stop()
+	}
+	go a.startBackendWorker()
+}
+
+// BackendReloadResult is the payload of "backend:reloaded" events
+type BackendReloadResult struct {
+	ScriptPath    string            `json:"script_path"`
+	WorkerRestart bool              `json:"worker_restart"` // whether a persistent worker was restarted
+	Environment   EnvironmentReport `json:"environment"`
+}
+
+// ReloadBackendScript picks up changes to the backend script without
+// restarting the app. It clears the result cache and the cached backend
+// version, restarts the persistent worker if one is running, and re-checks the
+// environment. Jobs run one process at a time use the new script anyway, so
+// for them this only clears the caches. It returns an error if the script is
+// missing or a required environment check fails.
+func (a *App) ReloadBackendScript() error {
+	scriptPath := filepath.Join(a.backendDir(), backendScriptName)
+	if _, err := os.Stat(scriptPath); err != nil {
+		return fmt.Errorf("backend script not found: %s", scriptPath)
+	}
+	if err := a.ClearCache(); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.backendVersion = nil
+	restart := a.backend != nil
+	a.mu.Unlock()
+	if restart {
+		a.restartBackendWorker()
+	}
+
+	result := BackendReloadResult{ScriptPath: scriptPath, WorkerRestart: restart, Environment: a.CheckEnvironment()}
+	a.logger.Info("backend script reloaded", map[string]interface{}{
+		"script_path":    scriptPath,
+		"worker_restart": restart,
+		"environment_ok": result.Environment.OK,
+	})
+	a.emit("backend:reloaded", result)
+
+	if !result.Environment.OK {
+		for _, check := range result.Environment.Checks {
+			if check.Required && !check.OK {
+				return fmt.Errorf("backend reloaded, but the %s check failed: %s", check.Name, check.Detail)
+			}
+		}
+	}
+	return nil
+}
+
+// reserveWorker returns the running worker, reserved for the request, if it
+// can serve it. Requests with their own environment, working directory or
+// debug settings need a dedicated process, as does any request made after the
+// working directory changed or while the worker is busy with another job.
+func (a *App) reserveWorker(request ProcessVideoRequest) *BackendManager {
+	a.mu.RLock()
+	backend := a.backend
+	a.mu.RUnlock()
+
+	if backend == nil || !backend.Running() {
+		return nil
+	}
+	if backend.dir != a.backendDir() {
+		return nil
+	}
+	if len(request.Env) > 0 || request.WorkingDir != "" || a.debugSettings().PythonDebug {
+		return nil
+	}
+	if !backend.tryReserve() {
+		return nil
+	}
+	return backend
+}
+
+// shutdown is called when the app is closing
+func (a *App) shutdown(ctx context.Context) {
+	a.mu.RLock()
+	backend := a.backend
+	history := a.history
+	watches := a.watches
+	outputs := a.outputWatches
+	api := a.api
+	a.mu.RUnlock()
+
+	_ = a.StopQueuePersistenceTicker()
+	if api != nil {
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		api.server.Shutdown(ctx)
+		cancel()
+	}
+	if watches != nil {
+		watches.stopAll()
+	}
+	if outputs != nil {
+		outputs.close()
+	}
+	if backend != nil {
+		backend.Stop()
+	}
+	if history != nil {
+		if err := history.Close(); err != nil {
+			a.logger.Warn("failed to close processing history", map[string]interface{}{"error": err.Error()})
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// backendScriptName is the entry point of the Python backend
+const backendScriptName = "process_video.py"
+
+// Interpreter strategies for running the backend script
+const (
+	interpreterUV     = "uv"     // uv run python, using the backend's uv-managed environment
+	interpreterPython = "python" // a specific Python executable
+	interpreterVenv   = "venv"   // the Python executable of an existing virtual environment
+)
+
+// interpreterStrategies lists the accepted values of Settings.Interpreter
+var interpreterStrategies = []string{interpreterUV, interpreterPython, interpreterVenv}
+
+// backendDir returns the folder containing the backend script. It is resolved
+// in this order: the backend path from the settings, the working directory set
+// with SetWorkingDirectory, the folder next to the executable (or in the
+// Resources folder of a macOS app bundle), the backend folder under the process
+// working directory, and finally the copy extracted from the binary. It returns
+// an empty string if none is available.
+func (a *App) backendDir() string {
+	a.mu.RLock()
+	dir := a.settings.BackendPath
+	workingDir := a.workingDir
+	embeddedDir := a.embeddedDir
+	a.mu.RUnlock()
+
+	if dir != "" {
+		return dir
+	}
+	if workingDir != "" {
+		return filepath.Join(workingDir, "backend")
+	}
+	for _, candidate := range executableBackendDirs() {
+		if hasBackendScript(candidate) {
+			return candidate
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err == nil && hasBackendScript(filepath.Join(cwd, "backend")) {
+		return filepath.Join(cwd, "backend")
+	}
+	if embeddedDir != "" {
+		return embeddedDir
+	}
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cwd, "backend")
+}
+
+// executableBackendDirs returns the places an installed backend is expected
+// relative to the running executable
+func executableBackendDirs() []string {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	exeDir := filepath.Dir(exe)
+
+	dirs := []string{filepath.Join(exeDir, "backend")}
+	if runtime.GOOS == "darwin" {
+		// <name>.app/Contents/MacOS/<exe> -> <name>.app/Contents/Resources/backend
+		dirs = append(dirs, filepath.Join(exeDir, "..", "Resources", "backend"))
+	}
+	return dirs
+}
+
+// hasBackendScript reports whether dir contains the backend script
+func hasBackendScript(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, backendScriptName))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// venvPython returns the Python executable inside a virtual environment
+func venvPython(venv string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(venv, "Scripts", "python.exe")
+	}
+	return filepath.Join(venv, "bin", "python")
+}
+
+// pythonCommand returns the program and arguments that run Python with args
+// according to the interpreter strategy in the settings
+func (a *App) pythonCommand(args ...string) (string, []string) {
+	a.mu.RLock()
+	settings := a.settings
+	a.mu.RUnlock()
+
+	switch settings.Interpreter {
+	case interpreterPython:
+		python := settings.PythonPath
+		if python == "" {
+			python = "python"
+		}
+		return python, args
+	case interpreterVenv:
+		return venvPython(settings.VenvPath), args
+	default:
+		return "uv", append([]string{"run", "python"}, args...)
+	}
+}
+
+// usesUV reports whether the backend is run through uv
+func (a *App) usesUV() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.settings.Interpreter == "" || a.settings.Interpreter == interpreterUV
+}
+
+// validateInterpreter checks the interpreter settings
+func validateInterpreter(settings Settings) error {
+	if settings.Interpreter != "" && !containsString(interpreterStrategies, settings.Interpreter) {
+		return fmt.Errorf("interpreter must be one of %v, got %q", interpreterStrategies, settings.Interpreter)
+	}
+	switch settings.Interpreter {
+	case interpreterPython:
+		python := settings.PythonPath
+		if python == "" {
+			python = "python"
+		}
+		if _, err := exec.LookPath(python); err != nil {
+			return fmt.Errorf("python interpreter not found: %s", python)
+		}
+	case interpreterVenv:
+		if settings.VenvPath == "" {
+			return fmt.Errorf("venv_path is required when the interpreter is %q", interpreterVenv)
+		}
+		if _, err := os.Stat(venvPython(settings.VenvPath)); err != nil {
+			return fmt.Errorf("venv_path does not contain a Python interpreter: %s", settings.VenvPath)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// versionProbeTimeout bounds each external version command run for the startup banner
+const versionProbeTimeout = 10 * time.Second
+
+// sensitiveKeyParts marks configuration keys whose values must not be logged
+var sensitiveKeyParts = []string{"key", "token", "secret", "password", "credential"}
+
+// LogStartupBanner writes a single INFO entry describing the resolved
+// configuration so logs show which settings a session actually ran with
+func (a *App) LogStartupBanner() error {
+	configFields, err := structToFields(a.config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize app config: %v", err)
+	}
+
+	backendDir := a.backendDir()
+	python, pythonArgs := a.pythonCommand("--version")
+	fields := map[string]interface{}{
+		"config":            redactFields(configFields),
+		"working_directory": a.GetWorkingDirectory(),
+		"script_path":       filepath.Join(backendDir, backendScriptName),
+		"python_version":    commandVersion(backendDir, python, pythonArgs...),
+		"ffmpeg_version":    commandVersion("", "ffmpeg", "-version"),
+	}
+
+	a.logger.Info("startup configuration", fields)
+	return nil
+}
+
+// structToFields converts a struct into a field map using its JSON tags
+func structToFields(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// redactFields replaces the values of sensitive-looking keys, recursing into nested maps
+func redactFields(fields map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		lowerKey := strings.ToLower(key)
+		sensitive := false
+		for _, part := range sensitiveKeyParts {
+			if strings.Contains(lowerKey, part) {
+				sensitive = true
+				break
+			}
+		}
+
+		switch {
+		case sensitive:
+			redacted[key] = "[REDACTED]"
+		case isFieldMap(value):
+			redacted[key] = redactFields(value.(map[string]interface{}))
+		default:
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+func isFieldMap(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
+
+// commandVersion returns the first output line of a version command, or a
+// description of why it could not be run
+func commandVersion(dir string, name string, args ...string) string {
+	version, err := runCheckCommand(dir, name, args...)
+	if err != nil {
+		return fmt.Sprintf("unavailable (%v)", err)
+	}
+	return version
+}
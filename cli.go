@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+)
+
+// Exit codes of the command line mode
+const (
+	cliExitOK        = 0
+	cliExitFailed    = 1 // processing returned an error
+	cliExitUsage     = 2 // invalid arguments
+	cliExitCancelled = 130
+)
+
+// cliUsage is printed for invalid invocations and -h
+const cliUsage = `Usage:
+  subkoma [--cli] process --input <video> --output <video> [options]
+
+Runs the same processing pipeline as the application without opening a window.
+Progress is written to stdout as it happens, followed by the result as JSON.
+
+Options:
+`
+
+// isCLIInvocation reports whether the arguments ask for the command line mode
+// instead of the window
+func isCLIInvocation(args []string) bool {
+	return len(args) > 0 && (args[0] == "--cli" || args[0] == "process")
+}
+
+// runCLI runs a command line invocation and returns the process exit code
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "--cli" {
+		args = args[1:]
+	}
+	if len(args) == 0 || args[0] != "process" {
+		fmt.Fprint(stderr, cliUsage)
+		fmt.Fprintln(stderr, "  (run `subkoma process -h` to list the options)")
+		return cliExitUsage
+	}
+
+	flags := flag.NewFlagSet("process", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, cliUsage)
+		flags.PrintDefaults()
+	}
+	input := flags.String("input", "", "video to process (required)")
+	output := flags.String("output", "", "where to write the processed video (required)")
+	config := flags.String("config", "", "analysis configuration: a JSON file, or inline JSON starting with {")
+	preset := flags.String("preset", "", "name of a saved preset to use as the configuration")
+	overwrite := flags.String("overwrite", "", "what to do if the output exists: overwrite, fail, auto-rename or skip")
+	timeout := flags.Int("timeout", 0, "stop the job after this many seconds; 0 uses the configured default")
+	noCache := flags.Bool("no-cache", false, "process even if a cached result matches")
+	verbose := flags.Bool("verbose", false, "also write log entries to stderr")
+	if err := flags.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return cliExitOK
+		}
+		return cliExitUsage
+	}
+	if *input == "" || *output == "" {
+		fmt.Fprintln(stderr, "--input and --output are required")
+		flags.Usage()
+		return cliExitUsage
+	}
+	if *config != "" && *preset != "" {
+		fmt.Fprintln(stderr, "--config and --preset cannot be combined")
+		return cliExitUsage
+	}
+
+	a := newCLIApp(*verbose)
+	defer a.shutdown(context.Background())
+
+	request := ProcessVideoRequest{
+		InputPath:       *input,
+		OutputPath:      *output,
+		OverwritePolicy: *overwrite,
+		TimeoutSeconds:  *timeout,
+		SkipCache:       *noCache,
+		JobID:           generateID(),
+	}
+	switch {
+	case *preset != "":
+		presetConfig, err := a.LoadPreset(*preset)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return cliExitUsage
+		}
+		request.AnalysisConfig = &presetConfig
+	case strings.HasPrefix(strings.TrimSpace(*config), "{"):
+		request.Config = *config
+	case *config != "":
+		data, err := os.ReadFile(*config)
+		if err != nil {
+			fmt.Fprintf(stderr, "cannot read config file: %v\n", err)
+			return cliExitUsage
+		}
+		request.Config = string(data)
+	default:
+		request.Config = "{}"
+	}
+
+	// Print progress as the backend reports it
+	a.onEvent = func(topic string, data interface{}) {
+		if progress, ok := data.(ProcessingProgress); ok && topic == "processing:progress" {
+			fmt.Fprintf(stdout, "%s %5.1f%% (frame %d/%d, about %.0fs left)\n",
+				progress.Stage, progress.Percent, progress.Frame, progress.TotalFrames, progress.ETASeconds)
+		}
+	}
+
+	// Ctrl+C cancels the job, which stops the backend and removes partial output
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		if _, ok := <-interrupts; ok {
+			a.CancelProcessing(request.JobID)
+		}
+	}()
+
+	response := a.ProcessVideo(request)
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(response)
+
+	switch response.Status {
+	case "success", "skipped":
+		return cliExitOK
+	case "cancelled":
+		return cliExitCancelled
+	default:
+		fmt.Fprintf(stderr, "%s: %s\n", response.ErrorType, response.Message)
+		return cliExitFailed
+	}
+}
+
+// newCLIApp creates an App for the command line mode. It loads the settings
+// and backend like startup does, but logs only to the log file unless verbose,
+// and does not show desktop notifications.
+func newCLIApp(verbose bool) *App {
+	var w io.Writer = io.Discard
+	if verbose {
+		w = os.Stderr
+	}
+	if dir, err := logDir(); err == nil {
+		if logFile, err := newRotatingFile(filepath.Join(dir, logFileName), maxLogFileSize, maxLogBackups); err == nil {
+			w = io.MultiWriter(w, logFile)
+		}
+	}
+
+	a := NewAppWithLogger(NewJSONLogger(w))
+	a.loadSettings()
+	a.prepareEmbeddedBackend()
+	a.mu.Lock()
+	a.config.NotifyOnCompletion = false
+	a.mu.Unlock()
+	return a
+}
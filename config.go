@@ -0,0 +1,36 @@
+package main
+
+import "os"
+
+// AppConfig holds application-wide settings used by the App methods
+type AppConfig struct {
+	// OutputDirMode is the permission mode used when creating missing output directories
+	OutputDirMode os.FileMode `json:"output_dir_mode"`
+	// MinBackendVersion and MaxBackendVersion bound the accepted backend
+	// script version. Empty values leave that side unbounded.
+	MinBackendVersion string `json:"min_backend_version,omitempty"`
+	MaxBackendVersion string `json:"max_backend_version,omitempty"`
+	// UsePersistentBackend keeps one Python worker running between jobs instead
+	// of starting a new interpreter for every request
+	UsePersistentBackend bool `json:"use_persistent_backend"`
+	// QueueConcurrency is how many queued jobs run at the same time
+	QueueConcurrency int `json:"queue_concurrency"`
+	// JobTimeoutSeconds stops a backend job that runs longer than this.
+	// Zero or negative disables the timeout.
+	JobTimeoutSeconds int `json:"job_timeout_seconds"`
+	// NotifyOnCompletion shows a desktop notification when a job finishes or fails
+	NotifyOnCompletion bool `json:"notify_on_completion"`
+}
+
+// DefaultAppConfig returns the configuration used when the app starts
+func DefaultAppConfig() AppConfig {
+	return AppConfig{
+		OutputDirMode:        0755,
+		MinBackendVersion:    minSupportedBackendVersion,
+		MaxBackendVersion:    maxSupportedBackendVersion,
+		UsePersistentBackend: true,
+		QueueConcurrency:     2,
+		JobTimeoutSeconds:    2 * 60 * 60,
+		NotifyOnCompletion:   true,
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// configAuditLogFileName is the append-only log of preset changes under the app data directory
+const configAuditLogFileName = "config_audit.log"
+
+// configAuditPreviewLength is how many characters of the parameters a config audit entry keeps
+const configAuditPreviewLength = 100
+
+// Config audit actions
+const (
+	configAuditLoad   = "load"
+	configAuditSave   = "save"
+	configAuditDelete = "delete"
+)
+
+// ConfigAuditEntry is one line of the config audit log
+type ConfigAuditEntry struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Action        string    `json:"action"` // "load", "save" or "delete"
+	ProfileName   string    `json:"profile_name"`
+	ConfigPreview string    `json:"config_preview"` // the first 100 characters of the parameters
+}
+
+// logConfigAuditEvent appends an entry to the config audit log
+func logConfigAuditEvent(action string, profileName string, configPreview string) error {
+	switch action {
+	case configAuditLoad, configAuditSave, configAuditDelete:
+	default:
+		return fmt.Errorf("unknown config audit action %q", action)
+	}
+	if preview := []rune(configPreview); len(preview) > configAuditPreviewLength {
+		configPreview = string(preview[:configAuditPreviewLength])
+	}
+	return appendAuditLine(configAuditLogFileName, ConfigAuditEntry{
+		Timestamp:     time.Now(),
+		Action:        action,
+		ProfileName:   profileName,
+		ConfigPreview: configPreview,
+	})
+}
+
+// auditPreset records a preset operation, logging instead of failing it when
+// the entry cannot be written
+func (a *App) auditPreset(action string, name string, config *AnalysisConfig) {
+	preview := ""
+	if config != nil {
+		preview, _ = config.Marshal()
+	}
+	if err := logConfigAuditEvent(action, name, preview); err != nil {
+		a.logger.Warn("failed to write config audit log", map[string]interface{}{
+			"action": action,
+			"preset": name,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// GetConfigAuditLog returns the last limit entries of the config audit log,
+// oldest first. A limit of zero or less returns every entry.
+func (a *App) GetConfigAuditLog(limit int) ([]ConfigAuditEntry, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, configAuditLogFileName)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []ConfigAuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config audit log: %v", err)
+	}
+	defer file.Close()
+
+	entries := []ConfigAuditEntry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry ConfigAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			a.logger.Warn("skipping unreadable config audit entry", map[string]interface{}{"path": path, "error": err.Error()})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config audit log: %v", err)
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// diagnosticsJobCount is how many recent history items ExportDiagnostics includes
+const diagnosticsJobCount = 50
+
+// SystemInfo describes the machine and build in a diagnostics bundle
+type SystemInfo struct {
+	OS             string      `json:"os"`
+	Arch           string      `json:"arch"`
+	CPUs           int         `json:"cpus"`
+	GoVersion      string      `json:"go_version"`
+	Revision       string      `json:"revision,omitempty"` // VCS revision the binary was built from
+	BackendDir     string      `json:"backend_dir"`
+	BackendVersion string      `json:"backend_version"`
+	EmbeddedHash   string      `json:"embedded_backend_hash,omitempty"`
+	GPUs           []GPUDevice `json:"gpus"`
+	CollectedAt    time.Time   `json:"collected_at"`
+}
+
+// systemInfo collects the SystemInfo of this session
+func (a *App) systemInfo() SystemInfo {
+	info := SystemInfo{
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		CPUs:        runtime.NumCPU(),
+		GoVersion:   runtime.Version(),
+		BackendDir:  a.backendDir(),
+		GPUs:        a.DetectGPUs(),
+		CollectedAt: time.Now(),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Revision = setting.Value
+			}
+		}
+	}
+	if version, err := a.GetBackendVersion(); err != nil {
+		info.BackendVersion = fmt.Sprintf("unavailable (%v)", err)
+	} else {
+		info.BackendVersion = version
+	}
+	info.EmbeddedHash, _ = embeddedBackendHash()
+	return info
+}
+
+// ExportDiagnostics writes a zip file for attaching to an issue report. It
+// holds system and build information, the environment check, the settings
+// with secrets redacted, the most recent jobs, the queue, and the log files.
+// The home directory is replaced with "~" throughout.
+func (a *App) ExportDiagnostics(destZip string) error {
+	if destZip == "" {
+		return fmt.Errorf("a destination file is required")
+	}
+	absPath, err := filepath.Abs(destZip)
+	if err != nil {
+		return fmt.Errorf("invalid destination %s: %v", destZip, err)
+	}
+	destZip = absPath
+
+	settingsFields, err := structToFields(a.GetSettings())
+	if err != nil {
+		return fmt.Errorf("failed to serialize settings: %v", err)
+	}
+	configFields, err := structToFields(a.config)
+	if err != nil {
+		return fmt.Errorf("failed to serialize app config: %v", err)
+	}
+
+	jobs := map[string]interface{}{
+		"queue":      a.jobQueue().State(),
+		"last_error": a.GetLastProcessingError(),
+	}
+	if history, err := a.GetHistory(HistoryFilter{Limit: diagnosticsJobCount}); err != nil {
+		jobs["history_error"] = err.Error()
+	} else {
+		jobs["history"] = history
+	}
+
+	documents := []struct {
+		name string
+		data interface{}
+	}{
+		{"system.json", a.systemInfo()},
+		{"environment.json", a.CheckEnvironment()},
+		{"settings.json", map[string]interface{}{
+			"settings": redactFields(settingsFields),
+			"config":   redactFields(configFields),
+		}},
+		{"jobs.json", jobs},
+	}
+
+	home, _ := os.UserHomeDir()
+	tmpPath := destZip + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", destZip, err)
+	}
+	defer os.Remove(tmpPath)
+
+	archive := zip.NewWriter(file)
+	for _, document := range documents {
+		data, err := json.MarshalIndent(document.data, "", "  ")
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to encode %s: %v", document.name, err)
+		}
+		if err := writeZipEntry(archive, document.name, redactHomeDir(data, home)); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := addLogFiles(archive, home); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %v", destZip, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", destZip, err)
+	}
+	if err := os.Rename(tmpPath, destZip); err != nil {
+		return fmt.Errorf("failed to write %s: %v", destZip, err)
+	}
+
+	a.logger.Info("diagnostics exported", map[string]interface{}{"path": destZip})
+	return nil
+}
+
+// addLogFiles copies the active and rotated log files into the archive's logs folder
+func addLogFiles(archive *zip.Writer, home string) error {
+	dir, err := logDir()
+	if err != nil {
+		return nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, logFileName+"*"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if err := writeZipEntry(archive, "logs/"+filepath.Base(path), redactHomeDir(data, home)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeZipEntry adds one file to the archive
+func writeZipEntry(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to the archive: %v", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s to the archive: %v", name, err)
+	}
+	return nil
+}
+
+// redactHomeDir replaces the home directory in data with "~", both as
+// written and in its JSON-escaped form, which differs on Windows
+func redactHomeDir(data []byte, home string) []byte {
+	if home == "" || home == "/" {
+		return data
+	}
+	text := string(data)
+	if escaped, err := json.Marshal(home); err == nil {
+		text = strings.ReplaceAll(text, strings.Trim(string(escaped), `"`), "~")
+	}
+	return []byte(strings.ReplaceAll(text, home, "~"))
+}
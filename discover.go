@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// videoExtensions are the file extensions treated as video inputs
+var videoExtensions = []string{".mp4", ".avi", ".mov", ".mkv", ".wmv", ".flv", ".webm"}
+
+// maxDiscoveredVideos caps how many files DiscoverVideos returns
+const maxDiscoveredVideos = 10000
+
+// discoverProbeWorkers is how many ffprobe processes DiscoverVideos runs at once
+const discoverProbeWorkers = 4
+
+// DiscoveredVideo is a candidate input found by DiscoverVideos
+type DiscoveredVideo struct {
+	Path            string    `json:"path"`
+	Name            string    `json:"name"`
+	SizeBytes       int64     `json:"size_bytes"`
+	ModifiedAt      time.Time `json:"modified_at"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	ProbeError      string    `json:"probe_error,omitempty"`
+}
+
+// isVideoFile reports whether path has a known video extension
+func isVideoFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, videoExt := range videoExtensions {
+		if ext == videoExt {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectVideoFolder opens a dialog to choose a folder of videos. It returns
+// an empty string if the dialog was cancelled.
+func (a *App) SelectVideoFolder() (string, error) {
+	if err := a.WaitForContext(contextReadyTimeout); err != nil {
+		return "", err
+	}
+
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select Video Folder",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to open folder dialog: %v", err)
+	}
+
+	return dir, nil
+}
+
+// DiscoverVideos lists the video files in dir, sorted by path. Subdirectories
+// are searched when recursive is set, skipping hidden ones. When ffprobe is
+// available each file's duration is filled in; files it cannot read are
+// still returned, with ProbeError set.
+func (a *App) DiscoverVideos(dir string, recursive bool) ([]DiscoveredVideo, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid folder %s: %v", dir, err)
+	}
+	info, err := os.Stat(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access folder %s: %v", absDir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a folder: %s", absDir)
+	}
+
+	videos := []DiscoveredVideo{}
+	err = filepath.WalkDir(absDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable subdirectories instead of aborting the whole walk
+			if d != nil && d.IsDir() && path != absDir {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != absDir && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isVideoFile(path) {
+			return nil
+		}
+		if len(videos) >= maxDiscoveredVideos {
+			return fs.SkipAll
+		}
+
+		fileInfo, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		videos = append(videos, DiscoveredVideo{
+			Path:       path,
+			Name:       d.Name(),
+			SizeBytes:  fileInfo.Size(),
+			ModifiedAt: fileInfo.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan folder %s: %v", absDir, err)
+	}
+
+	sort.Slice(videos, func(i, j int) bool { return videos[i].Path < videos[j].Path })
+
+	if _, err := exec.LookPath("ffprobe"); err == nil {
+		probeDiscoveredVideos(videos)
+	}
+
+	return videos, nil
+}
+
+// probeDiscoveredVideos fills in the duration of each video using a few ffprobe workers
+func probeDiscoveredVideos(videos []DiscoveredVideo) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < discoverProbeWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				duration, err := probeDuration(videos[i].Path)
+				if err != nil {
+					videos[i].ProbeError = err.Error()
+					continue
+				}
+				videos[i].DurationSeconds = duration
+			}
+		}()
+	}
+	for i := range videos {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
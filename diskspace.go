@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// outputSizeFactor scales the input size to the expected output size. The
+	// backend re-encodes with OpenCV's writer, which compresses less than
+	// typical camera or web encoders.
+	outputSizeFactor = 2
+	// minOutputBytesPerSecond bounds the estimate for inputs that are small
+	// only because they are heavily compressed
+	minOutputBytesPerSecond = 256 * 1024
+	// diskSpaceReserve is kept free on top of the estimate for temporary files
+	// and so the volume is not filled completely
+	diskSpaceReserve = 512 * 1024 * 1024
+)
+
+// DiskSpaceInfo is the result of CheckDiskSpace
+type DiskSpaceInfo struct {
+	Path           string `json:"path"`            // existing directory that was checked
+	FreeBytes      uint64 `json:"free_bytes"`      // available to the current user
+	RequiredBytes  uint64 `json:"required_bytes"`  // estimate plus the reserve
+	EstimatedBytes uint64 `json:"estimated_bytes"` // expected size of the output
+	Sufficient     bool   `json:"sufficient"`
+}
+
+// CheckDiskSpace reports whether the volume that will hold path has room for
+// estimatedBytes plus a safety reserve. path may be a file or directory that
+// does not exist yet; its nearest existing parent is checked.
+func (a *App) CheckDiskSpace(path string, estimatedBytes int64) (DiskSpaceInfo, error) {
+	if estimatedBytes < 0 {
+		return DiskSpaceInfo{}, fmt.Errorf("estimated size must not be negative, got %d", estimatedBytes)
+	}
+	dir, err := existingParentDir(path)
+	if err != nil {
+		return DiskSpaceInfo{}, err
+	}
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		return DiskSpaceInfo{}, fmt.Errorf("failed to read free disk space for %s: %v", dir, err)
+	}
+
+	required := uint64(estimatedBytes) + diskSpaceReserve
+	return DiskSpaceInfo{
+		Path:           dir,
+		FreeBytes:      free,
+		RequiredBytes:  required,
+		EstimatedBytes: uint64(estimatedBytes),
+		Sufficient:     free >= required,
+	}, nil
+}
+
+// existingParentDir returns path if it is an existing directory, otherwise its
+// nearest existing ancestor
+func existingParentDir(path string) (string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %v", path, err)
+	}
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no existing directory found for %s", path)
+		}
+		dir = parent
+	}
+}
+
+// estimateOutputBytes predicts the size of the processed video from the input's
+// size and, when ffprobe can read it, its duration
+func estimateOutputBytes(inputPath string) (int64, error) {
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return 0, err
+	}
+	estimate := info.Size() * outputSizeFactor
+	if duration, err := probeDuration(inputPath); err == nil {
+		if byDuration := int64(duration * minOutputBytesPerSecond); byDuration > estimate {
+			estimate = byDuration
+		}
+	}
+	return estimate, nil
+}
+
+// formatBytes renders a byte count for messages, e.g. "1.5 GB"
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// DroppedFile is a video accepted from a drag-and-drop onto the window
+type DroppedFile struct {
+	Path        string         `json:"path"`               // resolved absolute path
+	DroppedPath string         `json:"dropped_path"`       // path as reported by the drop, before resolving symlinks
+	Metadata    *VideoMetadata `json:"metadata,omitempty"` // nil if probing failed
+	ProbeError  string         `json:"probe_error,omitempty"`
+}
+
+// FileDropResult is the payload of "files:dropped" events
+type FileDropResult struct {
+	X        int            `json:"x"`
+	Y        int            `json:"y"`
+	Files    []DroppedFile  `json:"files"`
+	Rejected []RejectedFile `json:"rejected"`
+}
+
+// registerFileDrop starts handling files dropped onto the window
+func (a *App) registerFileDrop() {
+	runtime.OnFileDrop(a.ctx, func(x, y int, paths []string) {
+		// Probing runs ffprobe per file, so keep it off the UI callback
+		go a.handleFileDrop(x, y, paths)
+	})
+}
+
+// handleFileDrop validates dropped paths and emits the accepted videos, with
+// their probe metadata, as a "files:dropped" event
+func (a *App) handleFileDrop(x, y int, paths []string) {
+	result := a.checkDroppedFiles(paths)
+	result.X, result.Y = x, y
+
+	a.logger.Info("files dropped", map[string]interface{}{
+		"accepted": len(result.Files),
+		"rejected": len(result.Rejected),
+	})
+	a.emit("files:dropped", result)
+}
+
+// checkDroppedFiles resolves symlinks, drops duplicates and rejects paths that
+// are not readable video files. Accepted files are probed; a probe failure is
+// reported on the file rather than rejecting it.
+func (a *App) checkDroppedFiles(paths []string) FileDropResult {
+	result := FileDropResult{Files: []DroppedFile{}, Rejected: []RejectedFile{}}
+
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			result.Rejected = append(result.Rejected, RejectedFile{Path: path, Reason: fmt.Sprintf("cannot resolve path: %v", err)})
+			continue
+		}
+		resolved, err = filepath.Abs(resolved)
+		if err != nil {
+			result.Rejected = append(result.Rejected, RejectedFile{Path: path, Reason: err.Error()})
+			continue
+		}
+		if seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+
+		if !isVideoFile(resolved) {
+			result.Rejected = append(result.Rejected, RejectedFile{
+				Path:   path,
+				Reason: fmt.Sprintf("unsupported file type; expected one of %s", strings.Join(videoExtensions, ", ")),
+			})
+			continue
+		}
+		if err := checkReadableFile(resolved); err != nil {
+			result.Rejected = append(result.Rejected, RejectedFile{Path: path, Reason: err.Error()})
+			continue
+		}
+
+		file := DroppedFile{Path: resolved, DroppedPath: path}
+		if metadata, err := a.ProbeVideo(resolved); err != nil {
+			file.ProbeError = err.Error()
+		} else {
+			file.Metadata = &metadata
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	return result
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// backendFiles is the Python backend shipped inside the binary. Tests and
+// the analysis database are left out; the database is created next to the
+// extracted scripts on first use.
+//
+//go:embed backend/*.py backend/requirements.txt
+var backendFiles embed.FS
+
+// embeddedBackendRoot is the directory of backendFiles holding the scripts
+const embeddedBackendRoot = "backend"
+
+// embeddedBackendHash returns a digest of the embedded backend's file names
+// and contents, which identifies its version
+func embeddedBackendHash() (string, error) {
+	var names []string
+	err := fs.WalkDir(backendFiles, embeddedBackendRoot, func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			names = append(names, name)
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		data, err := backendFiles.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", name, len(data))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// backendDirHash returns a digest of the backend scripts in dir, computed like
+// embeddedBackendHash so an extracted copy of the embedded backend hashes the
+// same as the original
+func backendDirHash(dir string) (string, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.py"))
+	if err != nil {
+		return "", err
+	}
+	names = append(names, filepath.Join(dir, "requirements.txt"))
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", embeddedBackendRoot+"/"+filepath.Base(name), len(data))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// extractEmbeddedBackend writes the embedded backend to a directory named
+// after its hash under the app data directory and returns that directory. A
+// new version gets a new directory; files of an existing one are checked and
+// rewritten if they are missing or differ from the embedded copy.
+func extractEmbeddedBackend() (string, error) {
+	digest, err := embeddedBackendHash()
+	if err != nil {
+		return "", fmt.Errorf("failed to read embedded backend: %v", err)
+	}
+	base, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "backend", digest[:16])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backend directory %s: %v", dir, err)
+	}
+
+	err = fs.WalkDir(backendFiles, embeddedBackendRoot, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := backendFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, embeddedBackendRoot+"/")))
+		if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, data) {
+			return nil
+		}
+		return writeFileAtomic(target, data, 0644)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to extract backend to %s: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to name and renames it
+// into place, so readers never see a partially written file
+func writeFileAtomic(name string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	tmpPath := name + ".tmp"
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, name); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// prepareEmbeddedBackend extracts the embedded backend and remembers where, so
+// backendDir can fall back to it when no backend is installed on disk
+func (a *App) prepareEmbeddedBackend() {
+	dir, err := extractEmbeddedBackend()
+	if err != nil {
+		a.logger.Warn("embedded backend unavailable", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	a.mu.Lock()
+	a.embeddedDir = dir
+	a.mu.Unlock()
+	a.logger.Info("embedded backend ready", map[string]interface{}{"dir": dir})
+}
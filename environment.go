@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// optionalPythonPackages are development tools listed in requirements.txt
+// that processing does not need
+var optionalPythonPackages = map[string]bool{"pytest": true, "debugpy": true}
+
+// EnvironmentCheck is the result of one CheckEnvironment step
+type EnvironmentCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Required bool   `json:"required"`
+	Version  string `json:"version,omitempty"`
+	Detail   string `json:"detail"`
+	Fix      string `json:"fix,omitempty"` // what the user can do when the check fails
+}
+
+// EnvironmentReport summarizes whether video processing can run on this machine
+type EnvironmentReport struct {
+	// OK is true when every required check passed
+	OK              bool               `json:"ok"`
+	Checks          []EnvironmentCheck `json:"checks"`
+	MissingPackages []string           `json:"missing_packages"`
+	CheckedAt       time.Time          `json:"checked_at"`
+}
+
+// CheckEnvironment verifies the tools and files ProcessVideo depends on and
+// returns a report the UI can turn into setup instructions
+func (a *App) CheckEnvironment() EnvironmentReport {
+	backendDir := a.backendDir()
+	useUV := a.usesUV()
+	report := EnvironmentReport{MissingPackages: []string{}, CheckedAt: time.Now()}
+
+	// uv is still used to install dependencies when another interpreter runs the backend
+	uvCheck := EnvironmentCheck{Name: "uv", Required: useUV}
+	if _, err := exec.LookPath("uv"); err != nil {
+		uvCheck.Detail = "uv was not found on PATH."
+		uvCheck.Fix = "Install uv from https://docs.astral.sh/uv/ and restart the application."
+	} else if version, err := runCheckCommand("", "uv", "--version"); err != nil {
+		uvCheck.Detail = fmt.Sprintf("uv could not be run: %v", err)
+		uvCheck.Fix = "Reinstall uv and make sure it runs from a terminal."
+	} else {
+		uvCheck.OK = true
+		uvCheck.Version = version
+		uvCheck.Detail = "uv is installed."
+	}
+	report.Checks = append(report.Checks, uvCheck)
+
+	scriptPath := filepath.Join(backendDir, backendScriptName)
+	scriptCheck := EnvironmentCheck{Name: "backend_script", Required: true}
+	if _, err := os.Stat(scriptPath); err != nil {
+		scriptCheck.Detail = fmt.Sprintf("Backend script not found at %s.", scriptPath)
+		scriptCheck.Fix = "Run the application from its installation folder or set the backend path in the settings."
+	} else {
+		scriptCheck.OK = true
+		scriptCheck.Detail = scriptPath
+	}
+	report.Checks = append(report.Checks, scriptCheck)
+
+	pythonCheck := EnvironmentCheck{Name: "python", Required: true}
+	packagesCheck := EnvironmentCheck{Name: "python_packages", Required: true}
+	if useUV && !uvCheck.OK {
+		pythonCheck.Detail = "Skipped because uv is not available."
+		packagesCheck.Detail = "Skipped because uv is not available."
+	} else {
+		python, pythonArgs := a.pythonCommand("--version")
+		switch version, err := runCheckCommand(backendDir, python, pythonArgs...); {
+		case err != nil && useUV:
+			pythonCheck.Detail = fmt.Sprintf("Python could not be started through uv: %v", err)
+			pythonCheck.Fix = "Run `uv python install` and try again."
+		case err != nil:
+			pythonCheck.Detail = fmt.Sprintf("Python could not be started from %s: %v", python, err)
+			pythonCheck.Fix = "Check the interpreter in the settings."
+		default:
+			pythonCheck.OK = true
+			pythonCheck.Version = version
+			pythonCheck.Detail = fmt.Sprintf("Python is available through %s.", python)
+		}
+
+		// uv lists the backend's own environment; other interpreters list theirs with pip
+		lister, listerArgs := "uv", []string{"pip", "list", "--format", "json"}
+		if !useUV {
+			lister, listerArgs = a.pythonCommand("-m", "pip", "list", "--format", "json")
+		}
+		missing, err := missingPythonPackages(backendDir, lister, listerArgs...)
+		switch {
+		case err != nil:
+			packagesCheck.Detail = fmt.Sprintf("Installed packages could not be listed: %v", err)
+			packagesCheck.Fix = "Install the backend dependencies."
+		case len(missing) > 0:
+			report.MissingPackages = missing
+			packagesCheck.Detail = fmt.Sprintf("Missing Python packages: %s.", strings.Join(missing, ", "))
+			packagesCheck.Fix = "Install the backend dependencies."
+		default:
+			packagesCheck.OK = true
+			packagesCheck.Detail = "All required Python packages are installed."
+		}
+	}
+	report.Checks = append(report.Checks, pythonCheck, packagesCheck)
+
+	for _, tool := range []string{"ffmpeg", "ffprobe"} {
+		check := EnvironmentCheck{Name: tool}
+		if version, err := runCheckCommand("", tool, "-version"); err != nil {
+			check.Detail = fmt.Sprintf("%s is not available: %v", tool, err)
+			check.Fix = fmt.Sprintf("Install FFmpeg and make sure %s is on PATH to enable video inspection and conversion.", tool)
+		} else {
+			check.OK = true
+			check.Version = version
+			check.Detail = fmt.Sprintf("%s is installed.", tool)
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	report.OK = true
+	for _, check := range report.Checks {
+		if check.Required && !check.OK {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+// runCheckCommand runs a version-style command and returns the first line of its output
+func runCheckCommand(dir string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// requirementNamePattern extracts the distribution name from a requirements.txt line
+var requirementNamePattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// packageNameSeparators are collapsed by PEP 503 name normalization
+var packageNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePackageName applies PEP 503 name normalization
+func normalizePackageName(name string) string {
+	return strings.ToLower(packageNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// requiredPythonPackages returns the normalized names in backend/requirements.txt,
+// leaving out development-only tools
+func requiredPythonPackages(backendDir string) ([]string, error) {
+	file, err := os.Open(filepath.Join(backendDir, "requirements.txt"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var packages []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		match := requirementNamePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		name := normalizePackageName(match[1])
+		if !optionalPythonPackages[name] {
+			packages = append(packages, name)
+		}
+	}
+	return packages, scanner.Err()
+}
+
+// missingPythonPackages compares requirements.txt with the JSON package list
+// printed by name and args, such as `uv pip list --format json`
+func missingPythonPackages(backendDir string, name string, args ...string) ([]string, error) {
+	required, err := requiredPythonPackages(backendDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read requirements: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = backendDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var installed []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(out, &installed); err != nil {
+		return nil, fmt.Errorf("failed to parse package list: %v", err)
+	}
+	have := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		have[normalizePackageName(pkg.Name)] = true
+	}
+
+	missing := []string{}
+	for _, name := range required {
+		if !have[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// eventReplayCapacity is the number of events kept per topic
+	eventReplayCapacity = 100
+	// eventReplayMaxAge is how long an event stays available for replay
+	eventReplayMaxAge = 60 * time.Second
+)
+
+// bufferedEvent is an emitted event payload with its emission time
+type bufferedEvent struct {
+	data      interface{}
+	emittedAt time.Time
+}
+
+// EventReplayBuffer keeps the most recent events per topic so that UI
+// components mounted after an event fired can still retrieve it
+type EventReplayBuffer struct {
+	mu       sync.Mutex
+	capacity int
+	maxAge   time.Duration
+	topics   map[string][]bufferedEvent
+}
+
+// NewEventReplayBuffer creates a buffer holding up to capacity events per
+// topic, each for at most maxAge
+func NewEventReplayBuffer(capacity int, maxAge time.Duration) *EventReplayBuffer {
+	return &EventReplayBuffer{
+		capacity: capacity,
+		maxAge:   maxAge,
+		topics:   make(map[string][]bufferedEvent),
+	}
+}
+
+// Add records an event, dropping the oldest one when the topic is full
+func (b *EventReplayBuffer) Add(topic string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	events := b.evictExpired(topic, now)
+	if len(events) >= b.capacity {
+		events = append(events[:0], events[len(events)-b.capacity+1:]...)
+	}
+	b.topics[topic] = append(events, bufferedEvent{data: data, emittedAt: now})
+}
+
+// Get returns the buffered events of a topic, oldest first
+func (b *EventReplayBuffer) Get(topic string) []interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := b.evictExpired(topic, time.Now())
+	result := make([]interface{}, len(events))
+	for i, event := range events {
+		result[i] = event.data
+	}
+	return result
+}
+
+// evictExpired drops events older than maxAge and returns what remains.
+// The caller must hold b.mu.
+func (b *EventReplayBuffer) evictExpired(topic string, now time.Time) []bufferedEvent {
+	events := b.topics[topic]
+	expired := 0
+	for expired < len(events) && now.Sub(events[expired].emittedAt) > b.maxAge {
+		expired++
+	}
+	if expired == 0 {
+		return events
+	}
+
+	events = append(events[:0], events[expired:]...)
+	if len(events) == 0 {
+		delete(b.topics, topic)
+		return nil
+	}
+	b.topics[topic] = events
+	return events
+}
+
+// emit sends an event to the frontend and records it for replay. Events
+// raised before startup are only buffered, since there is no frontend to receive them yet.
+func (a *App) emit(topic string, data interface{}) {
+	a.events.Add(topic, data)
+	a.logEvent(topic, data)
+	if a.onEvent != nil {
+		a.onEvent(topic, data)
+	}
+	a.publishEvent(topic, data)
+	if !a.isContextReady() {
+		return
+	}
+	runtime.EventsEmit(a.ctx, topic, data)
+}
+
+// emittedEvent is an event as delivered to subscribers
+type emittedEvent struct {
+	topic string
+	data  interface{}
+}
+
+// subscribeEvents calls fn with every event emitted until the returned
+// function is called. fn runs on the emitting goroutine and must not block.
+func (a *App) subscribeEvents(fn func(topic string, data interface{})) (unsubscribe func()) {
+	a.subscribersMu.Lock()
+	defer a.subscribersMu.Unlock()
+
+	if a.subscribers == nil {
+		a.subscribers = make(map[int]func(string, interface{}))
+	}
+	a.nextSubscriber++
+	id := a.nextSubscriber
+	a.subscribers[id] = fn
+	return func() {
+		a.subscribersMu.Lock()
+		delete(a.subscribers, id)
+		a.subscribersMu.Unlock()
+	}
+}
+
+// publishEvent passes an event to the subscribers
+func (a *App) publishEvent(topic string, data interface{}) {
+	a.subscribersMu.Lock()
+	defer a.subscribersMu.Unlock()
+	for _, fn := range a.subscribers {
+		fn(topic, data)
+	}
+}
+
+// GetReplayBuffer returns the recent events of a topic so a newly mounted
+// component can catch up on what it missed
+func (a *App) GetReplayBuffer(topic string) []interface{} {
+	return a.events.Get(topic)
+}
+
+// eventLogEntry is one line of an event log file
+type eventLogEntry struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Topic     string      `json:"topic"`
+	Payload   interface{} `json:"payload"`
+}
+
+// EnableEventLogging appends every emitted event to outputPath as a JSON line
+func (a *App) EnableEventLogging(outputPath string) error {
+	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log %s: %v", outputPath, err)
+	}
+
+	a.eventLogMu.Lock()
+	previous := a.eventLog
+	a.eventLog = file
+	a.eventLogMu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// DisableEventLogging stops writing emitted events to the event log
+func (a *App) DisableEventLogging() error {
+	a.eventLogMu.Lock()
+	file := a.eventLog
+	a.eventLog = nil
+	a.eventLogMu.Unlock()
+
+	if file == nil {
+		return nil
+	}
+	return file.Close()
+}
+
+// logEvent writes an event to the event log when logging is enabled
+func (a *App) logEvent(topic string, data interface{}) {
+	a.eventLogMu.Lock()
+	defer a.eventLogMu.Unlock()
+	if a.eventLog == nil {
+		return
+	}
+
+	line, err := json.Marshal(eventLogEntry{Timestamp: time.Now(), Topic: topic, Payload: data})
+	if err != nil {
+		a.logger.Warn("failed to serialize event for event log", map[string]interface{}{
+			"topic": topic,
+			"error": err.Error(),
+		})
+		return
+	}
+	_, _ = a.eventLog.Write(append(line, '\n'))
+}
+
+// ReplayEventLog reads an event log written by EnableEventLogging and calls
+// handler for each entry in order, returning the number of events replayed
+func ReplayEventLog(logPath string, handler func(topic string, payload interface{})) (int, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open event log %s: %v", logPath, err)
+	}
+	defer file.Close()
+
+	count := 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry eventLogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return count, fmt.Errorf("invalid event log entry on line %d: %v", lineNumber, err)
+		}
+		handler(entry.Topic, entry.Payload)
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("failed to read event log %s: %v", logPath, err)
+	}
+
+	return count, nil
+}
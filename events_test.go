@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventReplayBufferCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		added    []interface{}
+		want     []interface{}
+	}{
+		{name: "empty", capacity: 3, added: nil, want: []interface{}{}},
+		{name: "below capacity", capacity: 3, added: []interface{}{1, 2}, want: []interface{}{1, 2}},
+		{name: "at capacity", capacity: 3, added: []interface{}{1, 2, 3}, want: []interface{}{1, 2, 3}},
+		{name: "oldest dropped", capacity: 3, added: []interface{}{1, 2, 3, 4, 5}, want: []interface{}{3, 4, 5}},
+		{name: "capacity one", capacity: 1, added: []interface{}{1, 2}, want: []interface{}{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewEventReplayBuffer(tt.capacity, eventReplayMaxAge)
+			for _, data := range tt.added {
+				buffer.Add("topic", data)
+			}
+			if got := buffer.Get("topic"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventReplayBufferTopicsAreSeparate(t *testing.T) {
+	buffer := NewEventReplayBuffer(2, eventReplayMaxAge)
+	buffer.Add("a", 1)
+	buffer.Add("b", 10)
+	buffer.Add("a", 2)
+	buffer.Add("a", 3)
+
+	// Filling topic a must not evict anything from topic b
+	if got, want := buffer.Get("a"), []interface{}{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Get(a) = %v, want %v", got, want)
+	}
+	if got, want := buffer.Get("b"), []interface{}{10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Get(b) = %v, want %v", got, want)
+	}
+	if got := buffer.Get("c"); len(got) != 0 {
+		t.Errorf("Get(c) = %v, want no events", got)
+	}
+}
+
+func TestEventReplayBufferMaxAge(t *testing.T) {
+	tests := []struct {
+		name string
+		ages []time.Duration // how long ago each event was emitted, oldest first
+		want []interface{}
+	}{
+		{name: "all fresh", ages: []time.Duration{30 * time.Second, time.Second}, want: []interface{}{0, 1}},
+		{name: "oldest expired", ages: []time.Duration{61 * time.Second, 59 * time.Second}, want: []interface{}{1}},
+		{name: "all expired", ages: []time.Duration{2 * time.Minute, 61 * time.Second}, want: []interface{}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewEventReplayBuffer(eventReplayCapacity, eventReplayMaxAge)
+			for i := range tt.ages {
+				buffer.Add("topic", i)
+			}
+			// Backdate the events instead of waiting for them to expire
+			now := time.Now()
+			for i, age := range tt.ages {
+				buffer.topics["topic"][i].emittedAt = now.Add(-age)
+			}
+
+			if got := buffer.Get("topic"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+			if len(tt.want) == 0 {
+				if _, ok := buffer.topics["topic"]; ok {
+					t.Error("expired topic still held in the buffer")
+				}
+			}
+		})
+	}
+}
+
+func TestEventReplayBufferAddEvictsExpired(t *testing.T) {
+	buffer := NewEventReplayBuffer(2, eventReplayMaxAge)
+	buffer.Add("topic", "expired")
+	buffer.Add("topic", "kept")
+	buffer.topics["topic"][0].emittedAt = time.Now().Add(-2 * eventReplayMaxAge)
+
+	// The expired event frees its slot, so the unexpired one is kept
+	buffer.Add("topic", "new")
+	if got, want := buffer.Get("topic"), []interface{}{"kept", "new"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %v, want %v", got, want)
+	}
+}
+
+func TestEmitBuffersForReplay(t *testing.T) {
+	app := newTestApp(t)
+	app.emit("job:progress", 10)
+	app.emit("job:progress", 20)
+
+	if got, want := app.GetReplayBuffer("job:progress"), []interface{}{10, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReplayBuffer() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// frameManifestName is the index written next to the exported images
+	frameManifestName = "index.json"
+	// frameExportTimeout bounds one export; the backend reads the whole video
+	frameExportTimeout = 30 * time.Minute
+)
+
+// frameImageFormats maps the accepted ExportFrames formats to file extensions
+var frameImageFormats = map[string]string{"png": "png", "jpg": "jpg", "jpeg": "jpg"}
+
+// KomaBox is the bounding box of the pose keypoints in a frame, in pixels
+type KomaBox struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ExportedKoma is one drawing in an ExportFrames manifest
+type ExportedKoma struct {
+	Index       int      `json:"index"` // position in the sequence, from 1
+	File        string   `json:"file"`  // image file name, relative to the manifest
+	FrameIndex  int      `json:"frame_index"`
+	Timestamp   float64  `json:"timestamp"` // seconds
+	Duration    float64  `json:"duration"`  // seconds the drawing stays on screen
+	HoldFrames  int      `json:"hold_frames"`
+	MotionState string   `json:"motion_state"`
+	BoundingBox *KomaBox `json:"bounding_box"` // nil unless the analysis saved keypoints
+}
+
+// FrameExportManifest is the index.json written by ExportFrames
+type FrameExportManifest struct {
+	JobID           string         `json:"job_id"`
+	DatabaseID      string         `json:"database_id"`
+	DatabasePath    string         `json:"database_path"`
+	SourceVideoPath string         `json:"source_video_path"`
+	Format          string         `json:"format"`
+	ExportedAt      time.Time      `json:"exported_at"`
+	Koma            []ExportedKoma `json:"koma"`
+}
+
+// FrameExportResult describes a finished ExportFrames call
+type FrameExportResult struct {
+	Dir          string `json:"dir"`
+	ManifestPath string `json:"manifest_path"`
+	Count        int    `json:"count"`
+}
+
+// komaFileName returns the deterministic image name of a drawing, so repeated
+// exports of the same analysis produce the same files
+func komaFileName(index, frameIndex int, ext string) string {
+	return fmt.Sprintf("koma_%05d_f%06d.%s", index, frameIndex, ext)
+}
+
+// komaBounds returns the box around a frame's keypoints, or nil without keypoints
+func komaBounds(keypoints []AnalysisKeypoint) *KomaBox {
+	if len(keypoints) == 0 {
+		return nil
+	}
+	minX, minY := keypoints[0].X, keypoints[0].Y
+	maxX, maxY := minX, minY
+	for _, kp := range keypoints[1:] {
+		minX, maxX = min(minX, kp.X), max(maxX, kp.X)
+		minY, maxY = min(minY, kp.Y), max(maxY, kp.Y)
+	}
+	return &KomaBox{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
+
+// komaSequence lays out the drawings of an analysis as an animator would:
+// within each run of frames with the same motion state, a new drawing every
+// hold count frames, e.g. every second frame while animating on 2s
+func komaSequence(frames []AnalysisFrame, ext string) []ExportedKoma {
+	frameDuration := analysisFrameDuration(frames)
+	var koma []ExportedKoma
+	for start := 0; start < len(frames); {
+		state := frames[start].MotionState
+		end := start + 1
+		for end < len(frames) && frames[end].MotionState == state {
+			end++
+		}
+		hold := max(motionStateHolds[state], 1)
+		for i := start; i < end; i += hold {
+			held := min(hold, end-i)
+			frame := frames[i]
+			koma = append(koma, ExportedKoma{
+				Index:       len(koma) + 1,
+				File:        komaFileName(len(koma)+1, frame.FrameIndex, ext),
+				FrameIndex:  frame.FrameIndex,
+				Timestamp:   frame.Timestamp,
+				Duration:    float64(held) * frameDuration,
+				HoldFrames:  held,
+				MotionState: state,
+				BoundingBox: komaBounds(frame.Keypoints),
+			})
+		}
+		start = end
+	}
+	return koma
+}
+
+// ExportFrames writes one image per drawing of the analysis databaseID in the
+// database file at databasePath, as reported in ProcessVideoResponse, to dir,
+// in format "png" or "jpg", together with an
+// index.json manifest of their timestamps, hold lengths and keypoint bounding
+// boxes. The backend extracts the frames from the source video; progress is
+// emitted as "frames:progress" events and the result as "frames:exported".
+func (a *App) ExportFrames(databasePath string, databaseID string, dir string, format string) (FrameExportResult, error) {
+	ext, ok := frameImageFormats[strings.ToLower(strings.TrimPrefix(format, "."))]
+	if !ok {
+		return FrameExportResult{}, fmt.Errorf("unsupported image format %q, expected png or jpg", format)
+	}
+	if dir == "" {
+		return FrameExportResult{}, fmt.Errorf("an output folder is required")
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return FrameExportResult{}, fmt.Errorf("invalid output folder %s: %v", dir, err)
+	}
+
+	record, err := a.findAnalysisRecord(databasePath, databaseID)
+	if err != nil {
+		return FrameExportResult{}, err
+	}
+	if len(record.FrameData) == 0 {
+		return FrameExportResult{}, fmt.Errorf("analysis %s has no frame data", databaseID)
+	}
+	if _, err := os.Stat(record.SourceVideoPath); err != nil {
+		return FrameExportResult{}, fmt.Errorf("cannot access the source video of analysis %s: %v", databaseID, err)
+	}
+
+	if err := os.MkdirAll(absDir, 0755); err != nil {
+		return FrameExportResult{}, fmt.Errorf("failed to create output folder %s: %v", absDir, err)
+	}
+	removePreviousFrameExport(absDir)
+
+	manifest := FrameExportManifest{
+		JobID:           record.JobID,
+		DatabaseID:      record.DatabaseID,
+		DatabasePath:    record.DatabasePath,
+		SourceVideoPath: record.SourceVideoPath,
+		Format:          ext,
+		Koma:            komaSequence(record.FrameData, ext),
+	}
+	exportID := generateID()
+	a.logger.Info("exporting frames", map[string]interface{}{
+		"export_id":   exportID,
+		"database_id": databaseID,
+		"dir":         absDir,
+		"count":       len(manifest.Koma),
+	})
+
+	type exportFrame struct {
+		FrameIndex int    `json:"frame_index"`
+		Path       string `json:"path"`
+	}
+	frames := make([]exportFrame, len(manifest.Koma))
+	for i, koma := range manifest.Koma {
+		frames[i] = exportFrame{FrameIndex: koma.FrameIndex, Path: filepath.Join(absDir, koma.File)}
+	}
+	params := map[string]interface{}{"input_path": record.SourceVideoPath, "frames": frames}
+	tracker := &progressTracker{app: a, jobID: exportID, inputPath: record.SourceVideoPath, event: "frames:progress"}
+	if err := a.runFrameExport(params, tracker); err != nil {
+		a.logger.Error("frame export failed", map[string]interface{}{"export_id": exportID, "error": err.Error()})
+		return FrameExportResult{}, err
+	}
+
+	// The backend reports success per request; check every image arrived
+	for _, frame := range frames {
+		if info, err := os.Stat(frame.Path); err != nil || info.Size() == 0 {
+			return FrameExportResult{}, fmt.Errorf("the backend did not write %s", frame.Path)
+		}
+	}
+
+	manifest.ExportedAt = time.Now()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return FrameExportResult{}, fmt.Errorf("failed to encode frame manifest: %v", err)
+	}
+	manifestPath := filepath.Join(absDir, frameManifestName)
+	if err := writeFileAtomic(manifestPath, data, 0644); err != nil {
+		return FrameExportResult{}, fmt.Errorf("failed to write frame manifest: %v", err)
+	}
+
+	result := FrameExportResult{Dir: absDir, ManifestPath: manifestPath, Count: len(manifest.Koma)}
+	a.logger.Info("frames exported", map[string]interface{}{"export_id": exportID, "dir": absDir, "count": result.Count})
+	a.emit("frames:exported", result)
+	return result, nil
+}
+
+// removePreviousFrameExport deletes the images listed by an earlier export to
+// dir, so a re-export with fewer drawings leaves no stale files behind
+func removePreviousFrameExport(dir string) {
+	data, err := os.ReadFile(filepath.Join(dir, frameManifestName))
+	if err != nil {
+		return
+	}
+	var previous FrameExportManifest
+	if json.Unmarshal(data, &previous) != nil {
+		return
+	}
+	for _, koma := range previous.Koma {
+		// Only plain names are ours; never follow a path out of dir
+		if koma.File == filepath.Base(koma.File) {
+			os.Remove(filepath.Join(dir, koma.File))
+		}
+	}
+}
+
+// runFrameExport sends an export_frames request to the persistent worker when
+// it is free, or runs the backend once with --export-frames otherwise
+func (a *App) runFrameExport(params map[string]interface{}, tracker *progressTracker) error {
+	ctx, cancel := context.WithTimeout(context.Background(), frameExportTimeout)
+	defer cancel()
+
+	var output []byte
+	worker := a.reserveWorker(ProcessVideoRequest{})
+	if worker != nil {
+		result, err := worker.ExportFrames(ctx, params, tracker)
+		// A worker that died after it was reserved never saw the request
+		if errors.Is(err, errWorkerNotRunning) {
+			worker = nil
+		} else if err != nil {
+			return fmt.Errorf("frame export failed: %v", err)
+		}
+		output = result
+	}
+	if worker == nil {
+		specFile, err := os.CreateTemp("", "subkoma-frames-*.json")
+		if err != nil {
+			return fmt.Errorf("failed to create frame export request: %v", err)
+		}
+		defer os.Remove(specFile.Name())
+		err = json.NewEncoder(specFile).Encode(params)
+		if closeErr := specFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write frame export request: %v", err)
+		}
+
+		python, args := a.pythonCommand(backendScriptName, "--export-frames", specFile.Name())
+		cmd := exec.CommandContext(ctx, python, args...)
+		cmd.Dir = a.backendDir()
+		cmd.Env = a.backendEnv(ProcessVideoRequest{})
+		setProcessGroup(cmd)
+		if a.GetSettings().LowPriority {
+			setLowPriority(cmd)
+		}
+		cmd.Cancel = func() error { return terminateProcessTree(cmd) }
+		cmd.WaitDelay = processKillGracePeriod
+
+		stdoutPipe, err := cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("failed to attach to backend output: %v", err)
+		}
+		stderrPipe, err := cmd.StderrPipe()
+		if err != nil {
+			return fmt.Errorf("failed to attach to backend output: %v", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start backend: %v", err)
+		}
+		var stderr lockedBuffer
+		stderrDone := make(chan struct{})
+		go func() {
+			defer close(stderrDone)
+			a.streamBackendLog(stderrPipe, func() string { return tracker.jobID }, &stderr)
+		}()
+		output = a.readBackendOutput(stdoutPipe, tracker)
+		<-stderrDone
+
+		if err := cmd.Wait(); err != nil {
+			if line := lastJSONLine(stderr.Bytes()); line != nil {
+				output = line
+			} else {
+				return fmt.Errorf("frame export failed: %v: %s", err, stderrExcerpt(strings.TrimSpace(string(stderr.Bytes()))))
+			}
+		}
+	}
+
+	var result struct {
+		Status    string `json:"status"`
+		ErrorType string `json:"error_type"`
+		Message   string `json:"message"`
+	}
+	if err := json.Unmarshal(lastJSONLine(output), &result); err != nil {
+		return fmt.Errorf("frame export failed: unexpected backend output: %s", stderrExcerpt(string(output)))
+	}
+	if result.Status != "success" {
+		return fmt.Errorf("frame export failed: %s: %s", result.ErrorType, result.Message)
+	}
+	return nil
+}
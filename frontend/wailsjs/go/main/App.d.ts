@@ -1,9 +1,212 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
+import {time} from '../models';
+
+export function AddWatchFolder(arg1:string,arg2:string,arg3:string):Promise<main.FolderWatch>;
+
+export function BenchmarkOutputDriveIOPS(arg1:string,arg2:number):Promise<main.IOBenchmarkResult>;
+
+export function CancelProcessing(arg1:string):Promise<main.ProcessVideoResponse>;
+
+export function CheckAudioVideoSync(arg1:string):Promise<main.AVSyncReport>;
+
+export function CheckDiskSpace(arg1:string,arg2:number):Promise<main.DiskSpaceInfo>;
+
+export function CheckEnvironment():Promise<main.EnvironmentReport>;
+
+export function CheckNetworkConnectivity(arg1:Array<string>):Promise<Record<string, boolean>>;
+
+export function ClearCache():Promise<void>;
+
+export function ClearFinishedJobs():Promise<void>;
+
+export function ClearLastProcessingError():Promise<void>;
+
+export function CompareActiveConfigToProfile(arg1:string,arg2:string):Promise<main.ConfigDiffResult>;
+
+export function CompareAnalysisRecords(arg1:string,arg2:string,arg3:string):Promise<main.AnalysisComparison>;
+
+export function ComputeOptimalResolution(arg1:string,arg2:number,arg3:string):Promise<main.OptimalResolution>;
+
+export function ConvertFrameRate(arg1:string,arg2:string,arg3:number):Promise<number>;
+
+export function DeleteHistoryItem(arg1:number):Promise<void>;
+
+export function DeletePreset(arg1:string):Promise<void>;
+
+export function DetectAudioSilence(arg1:string,arg2:number,arg3:number):Promise<main.SilenceReport>;
+
+export function DetectGPUs():Promise<Array<main.GPUDevice>>;
+
+export function DisableEventLogging():Promise<void>;
+
+export function DiscardInterruptedJobs():Promise<void>;
+
+export function DiscoverVideos(arg1:string,arg2:boolean):Promise<Array<main.DiscoveredVideo>>;
+
+export function EnableEventLogging(arg1:string):Promise<void>;
+
+export function EnqueueVideos(arg1:Array<main.ProcessVideoRequest>):Promise<Array<string>>;
+
+export function EnrichResponse(arg1:main.ProcessVideoResponse):Promise<main.ProcessVideoResponse>;
+
+export function ExecuteConditionalPipeline(arg1:Array<main.ConditionalStage>):Promise<Array<main.PipelineStageResult>>;
+
+export function ExpandInputGlob(arg1:string):Promise<Array<string>>;
+
+export function ExportDiagnostics(arg1:string):Promise<void>;
+
+export function ExportFrames(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.FrameExportResult>;
+
+export function ExportJobQueue(arg1:string):Promise<void>;
+
+export function ExportProcessingHistory(arg1:string,arg2:string,arg3:main.HistoryExportFilter):Promise<void>;
+
+export function ExportProfileAsEnvVars(arg1:string,arg2:string):Promise<Record<string, string>>;
+
+export function ExportSubtitles(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function ExtractFrameTimestamps(arg1:string):Promise<Array<main.FrameTimestamp>>;
+
+export function FilterQueueByMetadata(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function GenerateThumbnail(arg1:string,arg2:number):Promise<main.Thumbnail>;
+
+export function GetAPIServerInfo():Promise<main.APIServerInfo>;
+
+export function GetAnalysisResult(arg1:string,arg2:string):Promise<main.AnalysisResult>;
+
+export function GetBackendStatus():Promise<main.BackendStatus>;
+
+export function GetBackendVersion():Promise<string>;
+
+export function GetCacheStats():Promise<main.CacheStats>;
+
+export function GetConfigAuditLog(arg1:number):Promise<Array<main.ConfigAuditEntry>>;
+
+export function GetDefaultAnalysisConfig():Promise<main.AnalysisConfig>;
+
+export function GetHistory(arg1:main.HistoryFilter):Promise<Array<main.HistoryItem>>;
+
+export function GetHistoryItem(arg1:number):Promise<main.HistoryItem>;
+
+export function GetInterruptedJobs():Promise<Array<main.InterruptedJob>>;
+
+export function GetLastProcessingError():Promise<main.ProcessingErrorRecord>;
+
+export function GetLastRecoveredResult():Promise<main.ProcessVideoResponse>;
+
+export function GetNotificationsEnabled():Promise<boolean>;
+
+export function GetQueueState():Promise<main.QueueState>;
+
+export function GetRecentLogs(arg1:number):Promise<Array<Record<string, any>>>;
+
+export function GetReplayBuffer(arg1:string):Promise<Array<any>>;
+
+export function GetSettings():Promise<main.Settings>;
+
+export function GetSubprocessEnvironmentSnapshot(arg1:main.ProcessVideoRequest):Promise<Record<string, string>>;
+
+export function GetVideoChapters(arg1:string):Promise<Array<main.ChapterMark>>;
+
+export function GetVideoContainerInfo(arg1:string):Promise<main.ContainerInfo>;
+
+export function GetVideoStreamList(arg1:string):Promise<Array<main.StreamInfo>>;
+
+export function GetWorkingDirectory():Promise<string>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportJobQueue(arg1:string,arg2:boolean):Promise<number>;
+
+export function InstallBackendDependencies():Promise<main.InstallResult>;
+
+export function ListPresets():Promise<Array<main.Preset>>;
+
+export function ListWatchFolders():Promise<Array<main.FolderWatch>>;
+
+export function LoadPreset(arg1:string):Promise<main.AnalysisConfig>;
+
+export function LogStartupBanner():Promise<void>;
+
+export function NormalizeRotation(arg1:string,arg2:string):Promise<boolean>;
+
+export function OpenInDefaultPlayer(arg1:string):Promise<void>;
+
+export function OpenLogFolder():Promise<void>;
+
+export function PauseQueue():Promise<void>;
+
+export function PersistQueue():Promise<void>;
+
+export function ProbeVideo(arg1:string):Promise<main.VideoMetadata>;
+
 export function ProcessVideo(arg1:main.ProcessVideoRequest):Promise<main.ProcessVideoResponse>;
 
+export function ProcessVideoGlob(arg1:string,arg2:string,arg3:string):Promise<Array<main.ProcessVideoResponse>>;
+
+export function ProcessVideoWithAudit(arg1:main.ProcessVideoRequest,arg2:string,arg3:string):Promise<main.ProcessVideoResponse>;
+
+export function ProcessVideoWithPreviewValidation(arg1:main.ProcessVideoRequest,arg2:number):Promise<main.ProcessVideoResponse>;
+
+export function QueryDetections(arg1:string,arg2:string,arg3:main.DetectionFilter):Promise<main.DetectionPage>;
+
+export function ReloadBackendScript():Promise<void>;
+
+export function RemoveWatchFolder(arg1:string):Promise<void>;
+
+export function ResumeInterruptedJobs():Promise<Array<string>>;
+
+export function ResumeQueue():Promise<void>;
+
+export function RevealInFileManager(arg1:string):Promise<void>;
+
+export function RunJobsWithTag(arg1:string,arg2:string):Promise<number>;
+
+export function RunSelfTest():Promise<main.SelfTestReport>;
+
+export function SavePreset(arg1:string,arg2:main.AnalysisConfig):Promise<void>;
+
+export function ScheduleJob(arg1:main.ProcessVideoRequest,arg2:time.Time):Promise<string>;
+
+export function SelectOutputPath(arg1:string):Promise<string>;
+
 export function SelectVideoFile():Promise<string>;
+
+export function SelectVideoFiles():Promise<main.VideoFileSelection>;
+
+export function SelectVideoFolder():Promise<string>;
+
+export function SetHistoryRetentionPolicy(arg1:number,arg2:number):Promise<void>;
+
+export function SetNetworkRequiredForOperation(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetNotificationsEnabled(arg1:boolean):Promise<void>;
+
+export function SetQueueConcurrency(arg1:number):Promise<void>;
+
+export function SetQueuePersistenceInterval(arg1:time.Duration):Promise<void>;
+
+export function SetWatchFolderEnabled(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetWorkingDirectory(arg1:string):Promise<void>;
+
+export function SetupApplicationMenu():Promise<void>;
+
+export function StopQueuePersistenceTicker():Promise<void>;
+
+export function SubscribeToQueueStats(arg1:any):Promise<any>;
+
+export function TrimProcessingHistory(arg1:number):Promise<number>;
+
+export function UnwatchOutputFile(arg1:string):Promise<void>;
+
+export function UpdateSettings(arg1:main.Settings):Promise<main.Settings>;
+
+export function ValidateOutputDecoding(arg1:string,arg2:number):Promise<main.DecodingValidationResult>;
+
+export function WaitForContext(arg1:time.Duration):Promise<void>;
+
+export function WatchOutputFile(arg1:string):Promise<string>;
@@ -2,14 +2,418 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddWatchFolder(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddWatchFolder'](arg1, arg2, arg3);
+}
+
+export function BenchmarkOutputDriveIOPS(arg1, arg2) {
+  return window['go']['main']['App']['BenchmarkOutputDriveIOPS'](arg1, arg2);
+}
+
+export function CancelProcessing(arg1) {
+  return window['go']['main']['App']['CancelProcessing'](arg1);
+}
+
+export function CheckAudioVideoSync(arg1) {
+  return window['go']['main']['App']['CheckAudioVideoSync'](arg1);
+}
+
+export function CheckDiskSpace(arg1, arg2) {
+  return window['go']['main']['App']['CheckDiskSpace'](arg1, arg2);
+}
+
+export function CheckEnvironment() {
+  return window['go']['main']['App']['CheckEnvironment']();
+}
+
+export function CheckNetworkConnectivity(arg1) {
+  return window['go']['main']['App']['CheckNetworkConnectivity'](arg1);
+}
+
+export function ClearCache() {
+  return window['go']['main']['App']['ClearCache']();
+}
+
+export function ClearFinishedJobs() {
+  return window['go']['main']['App']['ClearFinishedJobs']();
+}
+
+export function ClearLastProcessingError() {
+  return window['go']['main']['App']['ClearLastProcessingError']();
+}
+
+export function CompareActiveConfigToProfile(arg1, arg2) {
+  return window['go']['main']['App']['CompareActiveConfigToProfile'](arg1, arg2);
+}
+
+export function CompareAnalysisRecords(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CompareAnalysisRecords'](arg1, arg2, arg3);
+}
+
+export function ComputeOptimalResolution(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ComputeOptimalResolution'](arg1, arg2, arg3);
+}
+
+export function ConvertFrameRate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConvertFrameRate'](arg1, arg2, arg3);
+}
+
+export function DeleteHistoryItem(arg1) {
+  return window['go']['main']['App']['DeleteHistoryItem'](arg1);
+}
+
+export function DeletePreset(arg1) {
+  return window['go']['main']['App']['DeletePreset'](arg1);
+}
+
+export function DetectAudioSilence(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DetectAudioSilence'](arg1, arg2, arg3);
+}
+
+export function DetectGPUs() {
+  return window['go']['main']['App']['DetectGPUs']();
+}
+
+export function DisableEventLogging() {
+  return window['go']['main']['App']['DisableEventLogging']();
+}
+
+export function DiscardInterruptedJobs() {
+  return window['go']['main']['App']['DiscardInterruptedJobs']();
+}
+
+export function DiscoverVideos(arg1, arg2) {
+  return window['go']['main']['App']['DiscoverVideos'](arg1, arg2);
+}
+
+export function EnableEventLogging(arg1) {
+  return window['go']['main']['App']['EnableEventLogging'](arg1);
+}
+
+export function EnqueueVideos(arg1) {
+  return window['go']['main']['App']['EnqueueVideos'](arg1);
+}
+
+export function EnrichResponse(arg1) {
+  return window['go']['main']['App']['EnrichResponse'](arg1);
+}
+
+export function ExecuteConditionalPipeline(arg1) {
+  return window['go']['main']['App']['ExecuteConditionalPipeline'](arg1);
+}
+
+export function ExpandInputGlob(arg1) {
+  return window['go']['main']['App']['ExpandInputGlob'](arg1);
+}
+
+export function ExportDiagnostics(arg1) {
+  return window['go']['main']['App']['ExportDiagnostics'](arg1);
+}
+
+export function ExportFrames(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportFrames'](arg1, arg2, arg3, arg4);
+}
+
+export function ExportJobQueue(arg1) {
+  return window['go']['main']['App']['ExportJobQueue'](arg1);
+}
+
+export function ExportProcessingHistory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportProcessingHistory'](arg1, arg2, arg3);
+}
+
+export function ExportProfileAsEnvVars(arg1, arg2) {
+  return window['go']['main']['App']['ExportProfileAsEnvVars'](arg1, arg2);
+}
+
+export function ExportSubtitles(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportSubtitles'](arg1, arg2, arg3, arg4);
+}
+
+export function ExtractFrameTimestamps(arg1) {
+  return window['go']['main']['App']['ExtractFrameTimestamps'](arg1);
+}
+
+export function FilterQueueByMetadata(arg1, arg2) {
+  return window['go']['main']['App']['FilterQueueByMetadata'](arg1, arg2);
+}
+
+export function GenerateThumbnail(arg1, arg2) {
+  return window['go']['main']['App']['GenerateThumbnail'](arg1, arg2);
+}
+
+export function GetAPIServerInfo() {
+  return window['go']['main']['App']['GetAPIServerInfo']();
+}
+
+export function GetAnalysisResult(arg1, arg2) {
+  return window['go']['main']['App']['GetAnalysisResult'](arg1, arg2);
+}
+
+export function GetBackendStatus() {
+  return window['go']['main']['App']['GetBackendStatus']();
+}
+
+export function GetBackendVersion() {
+  return window['go']['main']['App']['GetBackendVersion']();
+}
+
+export function GetCacheStats() {
+  return window['go']['main']['App']['GetCacheStats']();
+}
+
+export function GetConfigAuditLog(arg1) {
+  return window['go']['main']['App']['GetConfigAuditLog'](arg1);
+}
+
+export function GetDefaultAnalysisConfig() {
+  return window['go']['main']['App']['GetDefaultAnalysisConfig']();
+}
+
+export function GetHistory(arg1) {
+  return window['go']['main']['App']['GetHistory'](arg1);
+}
+
+export function GetHistoryItem(arg1) {
+  return window['go']['main']['App']['GetHistoryItem'](arg1);
+}
+
+export function GetInterruptedJobs() {
+  return window['go']['main']['App']['GetInterruptedJobs']();
+}
+
+export function GetLastProcessingError() {
+  return window['go']['main']['App']['GetLastProcessingError']();
+}
+
+export function GetLastRecoveredResult() {
+  return window['go']['main']['App']['GetLastRecoveredResult']();
+}
+
+export function GetNotificationsEnabled() {
+  return window['go']['main']['App']['GetNotificationsEnabled']();
+}
+
+export function GetQueueState() {
+  return window['go']['main']['App']['GetQueueState']();
+}
+
+export function GetRecentLogs(arg1) {
+  return window['go']['main']['App']['GetRecentLogs'](arg1);
+}
+
+export function GetReplayBuffer(arg1) {
+  return window['go']['main']['App']['GetReplayBuffer'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSubprocessEnvironmentSnapshot(arg1) {
+  return window['go']['main']['App']['GetSubprocessEnvironmentSnapshot'](arg1);
+}
+
+export function GetVideoChapters(arg1) {
+  return window['go']['main']['App']['GetVideoChapters'](arg1);
+}
+
+export function GetVideoContainerInfo(arg1) {
+  return window['go']['main']['App']['GetVideoContainerInfo'](arg1);
+}
+
+export function GetVideoStreamList(arg1) {
+  return window['go']['main']['App']['GetVideoStreamList'](arg1);
+}
+
+export function GetWorkingDirectory() {
+  return window['go']['main']['App']['GetWorkingDirectory']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportJobQueue(arg1, arg2) {
+  return window['go']['main']['App']['ImportJobQueue'](arg1, arg2);
+}
+
+export function InstallBackendDependencies() {
+  return window['go']['main']['App']['InstallBackendDependencies']();
+}
+
+export function ListPresets() {
+  return window['go']['main']['App']['ListPresets']();
+}
+
+export function ListWatchFolders() {
+  return window['go']['main']['App']['ListWatchFolders']();
+}
+
+export function LoadPreset(arg1) {
+  return window['go']['main']['App']['LoadPreset'](arg1);
+}
+
+export function LogStartupBanner() {
+  return window['go']['main']['App']['LogStartupBanner']();
+}
+
+export function NormalizeRotation(arg1, arg2) {
+  return window['go']['main']['App']['NormalizeRotation'](arg1, arg2);
+}
+
+export function OpenInDefaultPlayer(arg1) {
+  return window['go']['main']['App']['OpenInDefaultPlayer'](arg1);
+}
+
+export function OpenLogFolder() {
+  return window['go']['main']['App']['OpenLogFolder']();
+}
+
+export function PauseQueue() {
+  return window['go']['main']['App']['PauseQueue']();
+}
+
+export function PersistQueue() {
+  return window['go']['main']['App']['PersistQueue']();
+}
+
+export function ProbeVideo(arg1) {
+  return window['go']['main']['App']['ProbeVideo'](arg1);
+}
+
 export function ProcessVideo(arg1) {
   return window['go']['main']['App']['ProcessVideo'](arg1);
 }
 
+export function ProcessVideoGlob(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ProcessVideoGlob'](arg1, arg2, arg3);
+}
+
+export function ProcessVideoWithAudit(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ProcessVideoWithAudit'](arg1, arg2, arg3);
+}
+
+export function ProcessVideoWithPreviewValidation(arg1, arg2) {
+  return window['go']['main']['App']['ProcessVideoWithPreviewValidation'](arg1, arg2);
+}
+
+export function QueryDetections(arg1, arg2, arg3) {
+  return window['go']['main']['App']['QueryDetections'](arg1, arg2, arg3);
+}
+
+export function ReloadBackendScript() {
+  return window['go']['main']['App']['ReloadBackendScript']();
+}
+
+export function RemoveWatchFolder(arg1) {
+  return window['go']['main']['App']['RemoveWatchFolder'](arg1);
+}
+
+export function ResumeInterruptedJobs() {
+  return window['go']['main']['App']['ResumeInterruptedJobs']();
+}
+
+export function ResumeQueue() {
+  return window['go']['main']['App']['ResumeQueue']();
+}
+
+export function RevealInFileManager(arg1) {
+  return window['go']['main']['App']['RevealInFileManager'](arg1);
+}
+
+export function RunJobsWithTag(arg1, arg2) {
+  return window['go']['main']['App']['RunJobsWithTag'](arg1, arg2);
+}
+
+export function RunSelfTest() {
+  return window['go']['main']['App']['RunSelfTest']();
+}
+
+export function SavePreset(arg1, arg2) {
+  return window['go']['main']['App']['SavePreset'](arg1, arg2);
+}
+
+export function ScheduleJob(arg1, arg2) {
+  return window['go']['main']['App']['ScheduleJob'](arg1, arg2);
+}
+
+export function SelectOutputPath(arg1) {
+  return window['go']['main']['App']['SelectOutputPath'](arg1);
+}
+
 export function SelectVideoFile() {
   return window['go']['main']['App']['SelectVideoFile']();
 }
+
+export function SelectVideoFiles() {
+  return window['go']['main']['App']['SelectVideoFiles']();
+}
+
+export function SelectVideoFolder() {
+  return window['go']['main']['App']['SelectVideoFolder']();
+}
+
+export function SetHistoryRetentionPolicy(arg1, arg2) {
+  return window['go']['main']['App']['SetHistoryRetentionPolicy'](arg1, arg2);
+}
+
+export function SetNetworkRequiredForOperation(arg1, arg2) {
+  return window['go']['main']['App']['SetNetworkRequiredForOperation'](arg1, arg2);
+}
+
+export function SetNotificationsEnabled(arg1) {
+  return window['go']['main']['App']['SetNotificationsEnabled'](arg1);
+}
+
+export function SetQueueConcurrency(arg1) {
+  return window['go']['main']['App']['SetQueueConcurrency'](arg1);
+}
+
+export function SetQueuePersistenceInterval(arg1) {
+  return window['go']['main']['App']['SetQueuePersistenceInterval'](arg1);
+}
+
+export function SetWatchFolderEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetWatchFolderEnabled'](arg1, arg2);
+}
+
+export function SetWorkingDirectory(arg1) {
+  return window['go']['main']['App']['SetWorkingDirectory'](arg1);
+}
+
+export function SetupApplicationMenu() {
+  return window['go']['main']['App']['SetupApplicationMenu']();
+}
+
+export function StopQueuePersistenceTicker() {
+  return window['go']['main']['App']['StopQueuePersistenceTicker']();
+}
+
+export function SubscribeToQueueStats(arg1) {
+  return window['go']['main']['App']['SubscribeToQueueStats'](arg1);
+}
+
+export function TrimProcessingHistory(arg1) {
+  return window['go']['main']['App']['TrimProcessingHistory'](arg1);
+}
+
+export function UnwatchOutputFile(arg1) {
+  return window['go']['main']['App']['UnwatchOutputFile'](arg1);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
+export function ValidateOutputDecoding(arg1, arg2) {
+  return window['go']['main']['App']['ValidateOutputDecoding'](arg1, arg2);
+}
+
+export function WaitForContext(arg1) {
+  return window['go']['main']['App']['WaitForContext'](arg1);
+}
+
+export function WatchOutputFile(arg1) {
+  return window['go']['main']['App']['WatchOutputFile'](arg1);
+}
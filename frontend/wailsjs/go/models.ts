@@ -1,27 +1,1180 @@
 export namespace main {
 	
+	export class APIServerInfo {
+	    running: boolean;
+	    url?: string;
+	    token?: string;
+	    token_path?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIServerInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.url = source["url"];
+	        this.token = source["token"];
+	        this.token_path = source["token_path"];
+	    }
+	}
+	export class APIServerSettings {
+	    enabled: boolean;
+	    port: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIServerSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	    }
+	}
+	export class AVSyncReport {
+	    offset_ms: number;
+	    is_in_sync: boolean;
+	    measurement_method: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AVSyncReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.offset_ms = source["offset_ms"];
+	        this.is_in_sync = source["is_in_sync"];
+	        this.measurement_method = source["measurement_method"];
+	    }
+	}
+	export class FrameScoreDiff {
+	    frame: number;
+	    score_a: number;
+	    score_b: number;
+	    diff: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameScoreDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.frame = source["frame"];
+	        this.score_a = source["score_a"];
+	        this.score_b = source["score_b"];
+	        this.diff = source["diff"];
+	    }
+	}
+	export class MotionStateSummary {
+	    frames: number;
+	    seconds: number;
+	    mean_score: number;
+	    hold_frames: number;
+	    segments: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MotionStateSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.frames = source["frames"];
+	        this.seconds = source["seconds"];
+	        this.mean_score = source["mean_score"];
+	        this.hold_frames = source["hold_frames"];
+	        this.segments = source["segments"];
+	    }
+	}
+	export class AnalysisResult {
+	    job_id: string;
+	    database_id: string;
+	    database_path: string;
+	    source_video_path: string;
+	    output_video_path: string;
+	    analysis_timestamp: string;
+	    parameters: Record<string, any>;
+	    frame_count: number;
+	    duration_seconds: number;
+	    mean_score: number;
+	    max_score: number;
+	    has_keypoints: boolean;
+	    states: Record<string, MotionStateSummary>;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalysisResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.job_id = source["job_id"];
+	        this.database_id = source["database_id"];
+	        this.database_path = source["database_path"];
+	        this.source_video_path = source["source_video_path"];
+	        this.output_video_path = source["output_video_path"];
+	        this.analysis_timestamp = source["analysis_timestamp"];
+	        this.parameters = source["parameters"];
+	        this.frame_count = source["frame_count"];
+	        this.duration_seconds = source["duration_seconds"];
+	        this.mean_score = source["mean_score"];
+	        this.max_score = source["max_score"];
+	        this.has_keypoints = source["has_keypoints"];
+	        this.states = this.convertValues(source["states"], MotionStateSummary, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnalysisComparison {
+	    record_a: AnalysisResult;
+	    record_b: AnalysisResult;
+	    score_diff: number;
+	    frame_score_diffs: FrameScoreDiff[];
+	    summary: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalysisComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.record_a = this.convertValues(source["record_a"], AnalysisResult);
+	        this.record_b = this.convertValues(source["record_b"], AnalysisResult);
+	        this.score_diff = source["score_diff"];
+	        this.frame_score_diffs = this.convertValues(source["frame_score_diffs"], FrameScoreDiff);
+	        this.summary = source["summary"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MotionWeights {
+	    displacement: number;
+	    velocity: number;
+	    acceleration: number;
+	    direction_change: number;
+	    pose_change: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MotionWeights(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.displacement = source["displacement"];
+	        this.velocity = source["velocity"];
+	        this.acceleration = source["acceleration"];
+	        this.direction_change = source["direction_change"];
+	        this.pose_change = source["pose_change"];
+	    }
+	}
+	export class AnalysisConfig {
+	    threshold_high: number;
+	    threshold_low: number;
+	    hysteresis_margin: number;
+	    min_duration: number;
+	    smoothing_method: string;
+	    smoothing_alpha: number;
+	    motion_weights: MotionWeights;
+	    enable_tame_tsume: boolean;
+	    save_keypoints: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalysisConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.threshold_high = source["threshold_high"];
+	        this.threshold_low = source["threshold_low"];
+	        this.hysteresis_margin = source["hysteresis_margin"];
+	        this.min_duration = source["min_duration"];
+	        this.smoothing_method = source["smoothing_method"];
+	        this.smoothing_alpha = source["smoothing_alpha"];
+	        this.motion_weights = this.convertValues(source["motion_weights"], MotionWeights);
+	        this.enable_tame_tsume = source["enable_tame_tsume"];
+	        this.save_keypoints = source["save_keypoints"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AnalysisKeypoint {
+	    x: number;
+	    y: number;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalysisKeypoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.name = source["name"];
+	    }
+	}
+	export class AnalysisFrame {
+	    frame_index: number;
+	    timestamp: number;
+	    motion_intensity_score: number;
+	    motion_state: string;
+	    keypoints: AnalysisKeypoint[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalysisFrame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.frame_index = source["frame_index"];
+	        this.timestamp = source["timestamp"];
+	        this.motion_intensity_score = source["motion_intensity_score"];
+	        this.motion_state = source["motion_state"];
+	        this.keypoints = this.convertValues(source["keypoints"], AnalysisKeypoint);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class AudioStreamInfo {
+	    index: number;
+	    codec: string;
+	    sample_rate: number;
+	    channels: number;
+	    language?: string;
+	    bit_rate_kbps?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AudioStreamInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.codec = source["codec"];
+	        this.sample_rate = source["sample_rate"];
+	        this.channels = source["channels"];
+	        this.language = source["language"];
+	        this.bit_rate_kbps = source["bit_rate_kbps"];
+	    }
+	}
+	export class BackendStatus {
+	    running: boolean;
+	    pid?: number;
+	    restarts: number;
+	    started_at: time.Time;
+	    last_health_check: time.Time;
+	    healthy: boolean;
+	    last_error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackendStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.pid = source["pid"];
+	        this.restarts = source["restarts"];
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.last_health_check = this.convertValues(source["last_health_check"], time.Time);
+	        this.healthy = source["healthy"];
+	        this.last_error = source["last_error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CacheStats {
+	    entries: number;
+	    output_bytes: number;
+	    hits: number;
+	    misses: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = source["entries"];
+	        this.output_bytes = source["output_bytes"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	    }
+	}
+	export class ChapterMark {
+	    start_seconds: number;
+	    end_seconds: number;
+	    title: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChapterMark(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start_seconds = source["start_seconds"];
+	        this.end_seconds = source["end_seconds"];
+	        this.title = source["title"];
+	    }
+	}
 	export class ProcessVideoRequest {
 	    input_path: string;
 	    output_path: string;
 	    config: string;
+	    analysis_config?: AnalysisConfig;
+	    create_output_dir_if_missing?: boolean;
+	    deadline?: time.Time;
+	    env?: Record<string, string>;
+	    description?: string;
+	    working_dir?: string;
+	    fallback_config?: string;
+	    job_id?: string;
+	    timeout_seconds?: number;
+	    overwrite_policy?: string;
+	    retry_attempts?: number;
+	    skip_cache?: boolean;
+	    run_at?: time.Time;
+	    off_hours_only?: boolean;
+	    start_time?: number;
+	    end_time?: number;
+	    metadata?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessVideoRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.config = source["config"];
+	        this.analysis_config = this.convertValues(source["analysis_config"], AnalysisConfig);
+	        this.create_output_dir_if_missing = source["create_output_dir_if_missing"];
+	        this.deadline = this.convertValues(source["deadline"], time.Time);
+	        this.env = source["env"];
+	        this.description = source["description"];
+	        this.working_dir = source["working_dir"];
+	        this.fallback_config = source["fallback_config"];
+	        this.job_id = source["job_id"];
+	        this.timeout_seconds = source["timeout_seconds"];
+	        this.overwrite_policy = source["overwrite_policy"];
+	        this.retry_attempts = source["retry_attempts"];
+	        this.skip_cache = source["skip_cache"];
+	        this.run_at = this.convertValues(source["run_at"], time.Time);
+	        this.off_hours_only = source["off_hours_only"];
+	        this.start_time = source["start_time"];
+	        this.end_time = source["end_time"];
+	        this.metadata = source["metadata"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConditionalStage {
+	    request: ProcessVideoRequest;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConditionalStage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.request = this.convertValues(source["request"], ProcessVideoRequest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigAuditEntry {
+	    timestamp: time.Time;
+	    action: string;
+	    profile_name: string;
+	    config_preview: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigAuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timestamp = this.convertValues(source["timestamp"], time.Time);
+	        this.action = source["action"];
+	        this.profile_name = source["profile_name"];
+	        this.config_preview = source["config_preview"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConfigFieldDiff {
+	    key: string;
+	    active: any;
+	    profile: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigFieldDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.active = source["active"];
+	        this.profile = source["profile"];
+	    }
+	}
+	export class ConfigDiffResult {
+	    profile_name: string;
+	    equal: boolean;
+	    diffs: ConfigFieldDiff[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigDiffResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profile_name = source["profile_name"];
+	        this.equal = source["equal"];
+	        this.diffs = this.convertValues(source["diffs"], ConfigFieldDiff);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ContainerInfo {
+	    format_name: string;
+	    format_long_name: string;
+	    overall_bitrate_kbps: number;
+	    num_streams: number;
+	    tags: Record<string, string>;
+	    start_time: number;
+	    duration: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContainerInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format_name = source["format_name"];
+	        this.format_long_name = source["format_long_name"];
+	        this.overall_bitrate_kbps = source["overall_bitrate_kbps"];
+	        this.num_streams = source["num_streams"];
+	        this.tags = source["tags"];
+	        this.start_time = source["start_time"];
+	        this.duration = source["duration"];
+	    }
+	}
+	export class DebugSettings {
+	    python_debug: boolean;
+	    wait_for_client: boolean;
+	    port: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DebugSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.python_debug = source["python_debug"];
+	        this.wait_for_client = source["wait_for_client"];
+	        this.port = source["port"];
+	    }
+	}
+	export class DecodingValidationResult {
+	    decoded_frames: number;
+	    error_frames: number;
+	    corrupt_frames: number;
+	    is_valid: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DecodingValidationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.decoded_frames = source["decoded_frames"];
+	        this.error_frames = source["error_frames"];
+	        this.corrupt_frames = source["corrupt_frames"];
+	        this.is_valid = source["is_valid"];
+	    }
+	}
+	export class DetectionFilter {
+	    motion_states?: string[];
+	    min_score?: number;
+	    max_score?: number;
+	    start?: number;
+	    end?: number;
+	    limit?: number;
+	    offset?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DetectionFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.motion_states = source["motion_states"];
+	        this.min_score = source["min_score"];
+	        this.max_score = source["max_score"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
+	export class DetectionPage {
+	    total: number;
+	    detections: AnalysisFrame[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DetectionPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total = source["total"];
+	        this.detections = this.convertValues(source["detections"], AnalysisFrame);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiscoveredVideo {
+	    path: string;
+	    name: string;
+	    size_bytes: number;
+	    modified_at: time.Time;
+	    duration_seconds?: number;
+	    probe_error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscoveredVideo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.size_bytes = source["size_bytes"];
+	        this.modified_at = this.convertValues(source["modified_at"], time.Time);
+	        this.duration_seconds = source["duration_seconds"];
+	        this.probe_error = source["probe_error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DiskSpaceInfo {
+	    path: string;
+	    free_bytes: number;
+	    required_bytes: number;
+	    estimated_bytes: number;
+	    sufficient: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiskSpaceInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.free_bytes = source["free_bytes"];
+	        this.required_bytes = source["required_bytes"];
+	        this.estimated_bytes = source["estimated_bytes"];
+	        this.sufficient = source["sufficient"];
+	    }
+	}
+	export class EnvironmentCheck {
+	    name: string;
+	    ok: boolean;
+	    required: boolean;
+	    version?: string;
+	    detail: string;
+	    fix?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvironmentCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.ok = source["ok"];
+	        this.required = source["required"];
+	        this.version = source["version"];
+	        this.detail = source["detail"];
+	        this.fix = source["fix"];
+	    }
+	}
+	export class EnvironmentReport {
+	    ok: boolean;
+	    checks: EnvironmentCheck[];
+	    missing_packages: string[];
+	    checked_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new EnvironmentReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.checks = this.convertValues(source["checks"], EnvironmentCheck);
+	        this.missing_packages = source["missing_packages"];
+	        this.checked_at = this.convertValues(source["checked_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FolderWatch {
+	    id: string;
+	    dir: string;
+	    preset: string;
+	    output_template: string;
+	    enabled: boolean;
+	    created_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new FolderWatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.dir = source["dir"];
+	        this.preset = source["preset"];
+	        this.output_template = source["output_template"];
+	        this.enabled = source["enabled"];
+	        this.created_at = this.convertValues(source["created_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FrameExportResult {
+	    dir: string;
+	    manifest_path: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameExportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.manifest_path = source["manifest_path"];
+	        this.count = source["count"];
+	    }
+	}
+	
+	export class FrameTimestamp {
+	    frame_number: number;
+	    presentation_timestamp_ms: number;
+	    display_timestamp_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameTimestamp(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.frame_number = source["frame_number"];
+	        this.presentation_timestamp_ms = source["presentation_timestamp_ms"];
+	        this.display_timestamp_ms = source["display_timestamp_ms"];
+	    }
+	}
+	export class GPUDevice {
+	    id: string;
+	    kind: string;
+	    name: string;
+	    memory_mb?: number;
+	    driver?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GPUDevice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.memory_mb = source["memory_mb"];
+	        this.driver = source["driver"];
+	    }
+	}
+	export class HistoryExportFilter {
+	    start_time?: time.Time;
+	    end_time?: time.Time;
+	    status_filter?: string[];
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryExportFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start_time = this.convertValues(source["start_time"], time.Time);
+	        this.end_time = this.convertValues(source["end_time"], time.Time);
+	        this.status_filter = source["status_filter"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryFilter {
+	    status?: string;
+	    query?: string;
+	    since?: time.Time;
+	    until?: time.Time;
+	    limit?: number;
+	    offset?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.query = source["query"];
+	        this.since = this.convertValues(source["since"], time.Time);
+	        this.until = this.convertValues(source["until"], time.Time);
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryItem {
+	    id: number;
+	    job_id: string;
+	    input_path: string;
+	    output_path: string;
+	    config: string;
+	    status: string;
+	    error_type?: string;
+	    message: string;
+	    database_id?: string;
+	    database_path?: string;
+	    duration_seconds: number;
+	    attempt: number;
+	    started_at: time.Time;
+	    finished_at: time.Time;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProcessVideoRequest(source);
+	        return new HistoryItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.job_id = source["job_id"];
 	        this.input_path = source["input_path"];
 	        this.output_path = source["output_path"];
 	        this.config = source["config"];
+	        this.status = source["status"];
+	        this.error_type = source["error_type"];
+	        this.message = source["message"];
+	        this.database_id = source["database_id"];
+	        this.database_path = source["database_path"];
+	        this.duration_seconds = source["duration_seconds"];
+	        this.attempt = source["attempt"];
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.finished_at = this.convertValues(source["finished_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryRetentionSettings {
+	    max_entries: number;
+	    max_age_days: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryRetentionSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.max_entries = source["max_entries"];
+	        this.max_age_days = source["max_age_days"];
+	    }
+	}
+	export class IOBenchmarkResult {
+	    sequential_write_mbps: number;
+	    sequential_read_mbps: number;
+	    random_write_4k_iops: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IOBenchmarkResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sequential_write_mbps = source["sequential_write_mbps"];
+	        this.sequential_read_mbps = source["sequential_read_mbps"];
+	        this.random_write_4k_iops = source["random_write_4k_iops"];
+	    }
+	}
+	export class InstallResult {
+	    success: boolean;
+	    exit_code: number;
+	    message: string;
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InstallResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.exit_code = source["exit_code"];
+	        this.message = source["message"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class InterruptedJob {
+	    job_id: string;
+	    request: ProcessVideoRequest;
+	    temp_paths: string[];
+	    started_at: time.Time;
+	    pid: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new InterruptedJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.job_id = source["job_id"];
+	        this.request = this.convertValues(source["request"], ProcessVideoRequest);
+	        this.temp_paths = source["temp_paths"];
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.pid = source["pid"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class OffHoursSettings {
+	    start: string;
+	    end: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OffHoursSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	export class OptimalResolution {
+	    width: number;
+	    height: number;
+	    estimated_bitrate: string;
+	    estimated_size_mb: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OptimalResolution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.estimated_bitrate = source["estimated_bitrate"];
+	        this.estimated_size_mb = source["estimated_size_mb"];
 	    }
 	}
 	export class ProcessVideoResponse {
 	    status: string;
 	    output_video_path?: string;
 	    database_id?: string;
+	    database_path?: string;
 	    message: string;
 	    error_type?: string;
+	    job_id?: string;
+	    cached?: boolean;
+	    attempts?: number;
+	    output_watch_id?: string;
+	    output_duration_seconds?: number;
+	    output_size_bytes?: number;
+	    output_resolution?: string;
+	    output_codec?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ProcessVideoResponse(source);
@@ -32,8 +1185,557 @@ export namespace main {
 	        this.status = source["status"];
 	        this.output_video_path = source["output_video_path"];
 	        this.database_id = source["database_id"];
+	        this.database_path = source["database_path"];
 	        this.message = source["message"];
 	        this.error_type = source["error_type"];
+	        this.job_id = source["job_id"];
+	        this.cached = source["cached"];
+	        this.attempts = source["attempts"];
+	        this.output_watch_id = source["output_watch_id"];
+	        this.output_duration_seconds = source["output_duration_seconds"];
+	        this.output_size_bytes = source["output_size_bytes"];
+	        this.output_resolution = source["output_resolution"];
+	        this.output_codec = source["output_codec"];
+	    }
+	}
+	export class PipelineStageResult {
+	    stage_index: number;
+	    status: string;
+	    response: ProcessVideoResponse;
+	
+	    static createFrom(source: any = {}) {
+	        return new PipelineStageResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stage_index = source["stage_index"];
+	        this.status = source["status"];
+	        this.response = this.convertValues(source["response"], ProcessVideoResponse);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Preset {
+	    name: string;
+	    config: AnalysisConfig;
+	    updated_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new Preset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.config = this.convertValues(source["config"], AnalysisConfig);
+	        this.updated_at = this.convertValues(source["updated_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ProcessingErrorRecord {
+	    error_type: string;
+	    message: string;
+	    input_path: string;
+	    occurred_at: time.Time;
+	    job_id?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProcessingErrorRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.error_type = source["error_type"];
+	        this.message = source["message"];
+	        this.input_path = source["input_path"];
+	        this.occurred_at = this.convertValues(source["occurred_at"], time.Time);
+	        this.job_id = source["job_id"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueuedJob {
+	    job_id: string;
+	    input_path: string;
+	    output_path: string;
+	    description: string;
+	    state: string;
+	    enqueued_at: time.Time;
+	    started_at?: time.Time;
+	    finished_at?: time.Time;
+	    run_at?: time.Time;
+	    off_hours?: boolean;
+	    metadata?: Record<string, string>;
+	    response?: ProcessVideoResponse;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueuedJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.job_id = source["job_id"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.description = source["description"];
+	        this.state = source["state"];
+	        this.enqueued_at = this.convertValues(source["enqueued_at"], time.Time);
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.finished_at = this.convertValues(source["finished_at"], time.Time);
+	        this.run_at = this.convertValues(source["run_at"], time.Time);
+	        this.off_hours = source["off_hours"];
+	        this.metadata = source["metadata"];
+	        this.response = this.convertValues(source["response"], ProcessVideoResponse);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueueState {
+	    jobs: QueuedJob[];
+	    concurrency: number;
+	    paused: boolean;
+	    pending: number;
+	    running: number;
+	    done: number;
+	    failed: number;
+	    cancelled: number;
+	    skipped: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueueState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobs = this.convertValues(source["jobs"], QueuedJob);
+	        this.concurrency = source["concurrency"];
+	        this.paused = source["paused"];
+	        this.pending = source["pending"];
+	        this.running = source["running"];
+	        this.done = source["done"];
+	        this.failed = source["failed"];
+	        this.cancelled = source["cancelled"];
+	        this.skipped = source["skipped"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RejectedFile {
+	    path: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RejectedFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class SelfTestStage {
+	    name: string;
+	    passed: boolean;
+	    skipped?: boolean;
+	    message: string;
+	    duration_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelfTestStage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.passed = source["passed"];
+	        this.skipped = source["skipped"];
+	        this.message = source["message"];
+	        this.duration_ms = source["duration_ms"];
+	    }
+	}
+	export class SelfTestReport {
+	    passed: boolean;
+	    stages: SelfTestStage[];
+	    started_at: time.Time;
+	    finished_at: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new SelfTestReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.passed = source["passed"];
+	        this.stages = this.convertValues(source["stages"], SelfTestStage);
+	        this.started_at = this.convertValues(source["started_at"], time.Time);
+	        this.finished_at = this.convertValues(source["finished_at"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Settings {
+	    default_output_dir: string;
+	    queue_concurrency: number;
+	    job_timeout_seconds: number;
+	    notify_on_completion: boolean;
+	    low_priority: boolean;
+	    max_threads: number;
+	    device: string;
+	    retry_attempts: number;
+	    retry_delay_seconds: number;
+	    backend_path: string;
+	    interpreter: string;
+	    python_path: string;
+	    venv_path: string;
+	    debug: DebugSettings;
+	    api: APIServerSettings;
+	    off_hours: OffHoursSettings;
+	    history_retention: HistoryRetentionSettings;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.default_output_dir = source["default_output_dir"];
+	        this.queue_concurrency = source["queue_concurrency"];
+	        this.job_timeout_seconds = source["job_timeout_seconds"];
+	        this.notify_on_completion = source["notify_on_completion"];
+	        this.low_priority = source["low_priority"];
+	        this.max_threads = source["max_threads"];
+	        this.device = source["device"];
+	        this.retry_attempts = source["retry_attempts"];
+	        this.retry_delay_seconds = source["retry_delay_seconds"];
+	        this.backend_path = source["backend_path"];
+	        this.interpreter = source["interpreter"];
+	        this.python_path = source["python_path"];
+	        this.venv_path = source["venv_path"];
+	        this.debug = this.convertValues(source["debug"], DebugSettings);
+	        this.api = this.convertValues(source["api"], APIServerSettings);
+	        this.off_hours = this.convertValues(source["off_hours"], OffHoursSettings);
+	        this.history_retention = this.convertValues(source["history_retention"], HistoryRetentionSettings);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TimeRange {
+	    start_seconds: number;
+	    end_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimeRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start_seconds = source["start_seconds"];
+	        this.end_seconds = source["end_seconds"];
+	    }
+	}
+	export class SilenceReport {
+	    has_silent_segments: boolean;
+	    silent_segments: TimeRange[];
+	    total_silent_seconds: number;
+	    is_totally_silent: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SilenceReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.has_silent_segments = source["has_silent_segments"];
+	        this.silent_segments = this.convertValues(source["silent_segments"], TimeRange);
+	        this.total_silent_seconds = source["total_silent_seconds"];
+	        this.is_totally_silent = source["is_totally_silent"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StreamInfo {
+	    index: number;
+	    codec_type: string;
+	    codec_name: string;
+	    language: string;
+	    bit_rate: number;
+	    is_default: boolean;
+	    is_forced: boolean;
+	    width?: number;
+	    height?: number;
+	    sample_rate?: number;
+	    channels?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StreamInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.codec_type = source["codec_type"];
+	        this.codec_name = source["codec_name"];
+	        this.language = source["language"];
+	        this.bit_rate = source["bit_rate"];
+	        this.is_default = source["is_default"];
+	        this.is_forced = source["is_forced"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.sample_rate = source["sample_rate"];
+	        this.channels = source["channels"];
+	    }
+	}
+	export class Thumbnail {
+	    path: string;
+	    data_url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Thumbnail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.data_url = source["data_url"];
+	    }
+	}
+	
+	export class VideoFileSelection {
+	    files: string[];
+	    rejected: RejectedFile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new VideoFileSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = source["files"];
+	        this.rejected = this.convertValues(source["rejected"], RejectedFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class VideoMetadata {
+	    path: string;
+	    format_name: string;
+	    duration_seconds: number;
+	    bit_rate_kbps: number;
+	    size_bytes: number;
+	    width: number;
+	    height: number;
+	    frame_rate: number;
+	    video_codec: string;
+	    rotation: number;
+	    audio_streams: AudioStreamInfo[];
+	    supported: boolean;
+	    unsupported_reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new VideoMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.format_name = source["format_name"];
+	        this.duration_seconds = source["duration_seconds"];
+	        this.bit_rate_kbps = source["bit_rate_kbps"];
+	        this.size_bytes = source["size_bytes"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.frame_rate = source["frame_rate"];
+	        this.video_codec = source["video_codec"];
+	        this.rotation = source["rotation"];
+	        this.audio_streams = this.convertValues(source["audio_streams"], AudioStreamInfo);
+	        this.supported = source["supported"];
+	        this.unsupported_reason = source["unsupported_reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace time {
+	
+	export class Time {
+	
+	
+	    static createFrom(source: any = {}) {
+	        return new Time(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	
 	    }
 	}
 
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxGlobPatternLength is the exclusive upper bound on glob pattern length
+const maxGlobPatternLength = 500
+
+// ExpandInputGlob returns the files matching pattern, sorted. Besides the
+// usual filepath.Match syntax, a "**" path segment matches any number of
+// directories. A pattern whose leading directory does not exist matches
+// nothing, with or without "**".
+func (a *App) ExpandInputGlob(pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("glob pattern is empty")
+	}
+	if len(pattern) >= maxGlobPatternLength {
+		return nil, fmt.Errorf("glob pattern must be shorter than %d characters", maxGlobPatternLength)
+	}
+
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	for _, segment := range segments {
+		if segment == ".." {
+			return nil, fmt.Errorf("glob pattern must not contain path traversal: %s", pattern)
+		}
+	}
+
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %s: %v", pattern, err)
+		}
+		return regularFiles(matches), nil
+	}
+
+	// Walk from the longest literal prefix, which must not contain wildcards
+	root, rootSegments := globRoot(pattern)
+	remaining := segments[rootSegments:]
+	matches := []string{}
+	if _, err := os.Stat(filepath.FromSlash(root)); os.IsNotExist(err) {
+		return matches, nil
+	}
+
+	err := filepath.WalkDir(filepath.FromSlash(root), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable directories instead of aborting the whole expansion
+			if d != nil && d.IsDir() && path != filepath.FromSlash(root) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash(root), path)
+		if err != nil {
+			return nil
+		}
+		ok, err := matchGlobSegments(remaining, strings.Split(filepath.ToSlash(rel), "/"))
+		if err != nil {
+			return err
+		}
+		if ok {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand glob pattern %s: %v", pattern, err)
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globRoot returns the longest leading directory of pattern without
+// wildcards, in slash form, and how many segments of the pattern it spans
+func globRoot(pattern string) (string, int) {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	rootSegments := 0
+	for rootSegments < len(segments)-1 && !strings.ContainsAny(segments[rootSegments], "*?[") {
+		rootSegments++
+	}
+	root := strings.Join(segments[:rootSegments], "/")
+	if root == "" {
+		if strings.HasPrefix(filepath.ToSlash(pattern), "/") {
+			root = "/"
+		} else {
+			root = "."
+		}
+	}
+	return root, rootSegments
+}
+
+// matchGlobSegments matches path segments against pattern segments, where
+// "**" matches zero or more whole segments
+func matchGlobSegments(pattern []string, path []string) (bool, error) {
+	if len(pattern) == 0 {
+		return len(path) == 0, nil
+	}
+
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(path); skip++ {
+			ok, err := matchGlobSegments(pattern[1:], path[skip:])
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+
+	if len(path) == 0 {
+		return false, nil
+	}
+	ok, err := filepath.Match(pattern[0], path[0])
+	if err != nil || !ok {
+		return false, err
+	}
+	return matchGlobSegments(pattern[1:], path[1:])
+}
+
+// regularFiles filters paths down to regular files
+func regularFiles(paths []string) []string {
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// ProcessVideoGlob adds every file matching pattern to the batch queue with
+// the same config, writing "<name>_processed<ext>" files into outputDir, and
+// returns a pending response with the job ID of each. The jobs follow the
+// queue's concurrency, pause and cancellation like any other queued job.
+// Files found in subfolders of the pattern's literal leading directory are
+// written to the same subfolders of outputDir, so equal names in different
+// folders do not overwrite each other.
+func (a *App) ProcessVideoGlob(pattern string, outputDir string, config string) []ProcessVideoResponse {
+	inputs, err := a.ExpandInputGlob(pattern)
+	if err != nil {
+		return []ProcessVideoResponse{{
+			Status:    "error",
+			ErrorType: "ValidationError",
+			Message:   err.Error(),
+		}}
+	}
+	if len(inputs) == 0 {
+		return []ProcessVideoResponse{{
+			Status:    "error",
+			ErrorType: "FileNotFoundError",
+			Message:   fmt.Sprintf("No files match the pattern %s.", pattern),
+		}}
+	}
+
+	root, _ := globRoot(pattern)
+	requests := make([]ProcessVideoRequest, 0, len(inputs))
+	for _, input := range inputs {
+		rel, err := filepath.Rel(filepath.FromSlash(root), input)
+		if err != nil || !filepath.IsLocal(rel) {
+			rel = filepath.Base(input)
+		}
+		ext := filepath.Ext(rel)
+		requests = append(requests, ProcessVideoRequest{
+			InputPath:  input,
+			OutputPath: filepath.Join(outputDir, strings.TrimSuffix(rel, ext)+"_processed"+ext),
+			Config:     config,
+		})
+	}
+
+	jobIDs := a.EnqueueVideos(requests)
+	responses := make([]ProcessVideoResponse, len(jobIDs))
+	for i, jobID := range jobIDs {
+		responses[i] = ProcessVideoResponse{
+			Status:  jobStatePending,
+			JobID:   jobID,
+			Message: fmt.Sprintf("%s was added to the queue.", requests[i].InputPath),
+		}
+	}
+	return responses
+}
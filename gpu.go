@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Device kinds accepted by the device setting besides automatic selection ("")
+const (
+	deviceCPU      = "cpu"
+	deviceCUDA     = "cuda"
+	deviceMetal    = "metal"
+	deviceDirectML = "directml"
+)
+
+// gpuProbeTimeout bounds each external command run by DetectGPUs
+const gpuProbeTimeout = 10 * time.Second
+
+// gpuFailureMarkers are stderr fragments that show the backend died while
+// setting up or using hardware acceleration
+var gpuFailureMarkers = []string{
+	"GPUNotAvailableError",
+	"CUDA error",
+	"CUDA driver",
+	"no CUDA-capable device",
+	"cudaError",
+	"GPU delegate",
+	"DirectML",
+	"MTLCreateSystemDefaultDevice",
+}
+
+// GPUDevice is an accelerator the backend can be pinned to
+type GPUDevice struct {
+	ID       string `json:"id"`   // value for the device setting, e.g. "cuda:0"
+	Kind     string `json:"kind"` // cuda, metal or directml
+	Name     string `json:"name"`
+	MemoryMB int64  `json:"memory_mb,omitempty"`
+	Driver   string `json:"driver,omitempty"`
+}
+
+// parseDevice splits a device setting such as "cuda:1" into its kind and
+// index. The index defaults to 0; "" and "cpu" have none.
+func parseDevice(device string) (string, int, error) {
+	kind, indexText, hasIndex := strings.Cut(strings.ToLower(strings.TrimSpace(device)), ":")
+	switch kind {
+	case "", deviceCPU:
+		if hasIndex {
+			return "", 0, fmt.Errorf("device %q does not take an index", device)
+		}
+		return kind, 0, nil
+	case deviceCUDA, deviceMetal, deviceDirectML:
+	default:
+		return "", 0, fmt.Errorf("unknown device %q, expected cpu, cuda[:N], metal[:N] or directml[:N]", device)
+	}
+	if !hasIndex {
+		return kind, 0, nil
+	}
+	index, err := strconv.Atoi(indexText)
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid device index in %q", device)
+	}
+	return kind, index, nil
+}
+
+// deviceEnv returns the variables that pin the backend to a device. CUDA
+// devices are selected through CUDA_VISIBLE_DEVICES, which renumbers the
+// chosen one to 0 inside the process; CPU mode hides CUDA devices entirely.
+func deviceEnv(device string) []string {
+	kind, index, err := parseDevice(device)
+	if err != nil || kind == "" {
+		return nil
+	}
+	switch kind {
+	case deviceCPU:
+		return []string{"SUBKOMA_DEVICE=cpu", "CUDA_VISIBLE_DEVICES=-1"}
+	case deviceCUDA:
+		return []string{"SUBKOMA_DEVICE=cuda", "SUBKOMA_DEVICE_INDEX=0", "CUDA_VISIBLE_DEVICES=" + strconv.Itoa(index)}
+	default:
+		return []string{"SUBKOMA_DEVICE=" + kind, "SUBKOMA_DEVICE_INDEX=" + strconv.Itoa(index)}
+	}
+}
+
+// isGPUFailure reports whether backend stderr shows an acceleration failure
+func isGPUFailure(stderr string) bool {
+	for _, marker := range gpuFailureMarkers {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// gpuProbe is a device list found while the device setting had a given value
+type gpuProbe struct {
+	device  string // device setting at the time of the probe
+	devices []GPUDevice
+}
+
+// DetectGPUs lists the accelerators found on this machine: NVIDIA GPUs
+// through nvidia-smi, plus Metal devices on macOS and DirectML adapters on
+// Windows. A machine without any returns an empty list.
+func (a *App) DetectGPUs() []GPUDevice {
+	device := a.GetSettings().Device
+	devices, ok := a.probeGPUs()
+	if ok {
+		a.mu.Lock()
+		a.gpus = &gpuProbe{device: device, devices: devices}
+		a.mu.Unlock()
+	}
+	return devices
+}
+
+// probeGPUs runs the detection commands and reports whether all of them succeeded
+func (a *App) probeGPUs() ([]GPUDevice, bool) {
+	devices := []GPUDevice{}
+	probes := []func() ([]GPUDevice, error){detectCUDADevices}
+	switch runtime.GOOS {
+	case "darwin":
+		probes = append(probes, detectMetalDevices)
+	case "windows":
+		probes = append(probes, detectDirectMLDevices)
+	}
+
+	ok := true
+	for _, probe := range probes {
+		found, err := probe()
+		if err != nil {
+			a.logger.Debug("GPU probe failed", map[string]interface{}{"error": err.Error()})
+			ok = false
+			continue
+		}
+		devices = append(devices, found...)
+	}
+	return devices, ok
+}
+
+// cachedGPUs returns the devices found by the last successful probe. The
+// probe is repeated once the device setting changes or after a failed probe.
+func (a *App) cachedGPUs() []GPUDevice {
+	a.mu.RLock()
+	device := a.settings.Device
+	cached := a.gpus
+	a.mu.RUnlock()
+	if cached != nil && cached.device == device {
+		return cached.devices
+	}
+	return a.DetectGPUs()
+}
+
+// runGPUProbe runs a detection command and returns its stdout
+func runGPUProbe(name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gpuProbeTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %v", name, err)
+	}
+	return output, nil
+}
+
+// detectCUDADevices parses the device list printed by nvidia-smi
+func detectCUDADevices() ([]GPUDevice, error) {
+	output, err := runGPUProbe("nvidia-smi", "--query-gpu=index,name,memory.total,driver_version", "--format=csv,noheader,nounits")
+	if err != nil || output == nil {
+		return nil, err
+	}
+	return parseNvidiaSMI(string(output)), nil
+}
+
+// parseNvidiaSMI parses "index, name, memory MiB, driver" CSV lines
+func parseNvidiaSMI(output string) []GPUDevice {
+	var devices []GPUDevice
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		memory, _ := strconv.ParseInt(fields[2], 10, 64)
+		devices = append(devices, GPUDevice{
+			ID:       fmt.Sprintf("%s:%d", deviceCUDA, index),
+			Kind:     deviceCUDA,
+			Name:     fields[1],
+			MemoryMB: memory,
+			Driver:   fields[3],
+		})
+	}
+	return devices
+}
+
+// detectMetalDevices lists the display adapters reported by system_profiler
+func detectMetalDevices() ([]GPUDevice, error) {
+	output, err := runGPUProbe("system_profiler", "SPDisplaysDataType", "-json")
+	if err != nil || output == nil {
+		return nil, err
+	}
+	var report struct {
+		Displays []struct {
+			Model string `json:"sppci_model"`
+			Metal string `json:"spdisplays_mtlgpufamilysupport"`
+		} `json:"SPDisplaysDataType"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse system_profiler output: %v", err)
+	}
+
+	var devices []GPUDevice
+	for _, display := range report.Displays {
+		index := len(devices)
+		devices = append(devices, GPUDevice{
+			ID:     fmt.Sprintf("%s:%d", deviceMetal, index),
+			Kind:   deviceMetal,
+			Name:   display.Model,
+			Driver: display.Metal,
+		})
+	}
+	return devices, nil
+}
+
+// detectDirectMLDevices lists the video controllers known to Windows. Every
+// DirectX 12 adapter can run DirectML; WMI does not report the DirectX level,
+// so all adapters are listed.
+func detectDirectMLDevices() ([]GPUDevice, error) {
+	output, err := runGPUProbe("powershell", "-NoProfile", "-NonInteractive", "-Command",
+		"Get-CimInstance Win32_VideoController | Select-Object Name,AdapterRAM,DriverVersion | ConvertTo-Json")
+	if err != nil || output == nil {
+		return nil, err
+	}
+	type controller struct {
+		Name          string `json:"Name"`
+		AdapterRAM    int64  `json:"AdapterRAM"`
+		DriverVersion string `json:"DriverVersion"`
+	}
+	// ConvertTo-Json writes a bare object when there is a single adapter
+	var controllers []controller
+	if err := json.Unmarshal(output, &controllers); err != nil {
+		var single controller
+		if err := json.Unmarshal(output, &single); err != nil {
+			return nil, fmt.Errorf("failed to parse video controller list: %v", err)
+		}
+		controllers = []controller{single}
+	}
+
+	var devices []GPUDevice
+	for i, c := range controllers {
+		devices = append(devices, GPUDevice{
+			ID:       fmt.Sprintf("%s:%d", deviceDirectML, i),
+			Kind:     deviceDirectML,
+			Name:     c.Name,
+			MemoryMB: c.AdapterRAM / (1024 * 1024),
+			Driver:   c.DriverVersion,
+		})
+	}
+	return devices, nil
+}
+
+// checkPinnedDevice returns a GPUNotAvailableError response when the
+// settings pin the backend to a device that is not present, and nil otherwise
+func (a *App) checkPinnedDevice() *ProcessVideoResponse {
+	kind, index, err := parseDevice(a.GetSettings().Device)
+	if err != nil || kind == "" || kind == deviceCPU {
+		return nil
+	}
+	id := fmt.Sprintf("%s:%d", kind, index)
+
+	devices := a.cachedGPUs()
+	names := make([]string, 0, len(devices))
+	for _, device := range devices {
+		if device.ID == id {
+			return nil
+		}
+		names = append(names, device.ID)
+	}
+	detected := "none"
+	if len(names) > 0 {
+		detected = strings.Join(names, ", ")
+	}
+	return &ProcessVideoResponse{
+		Status:    "error",
+		ErrorType: "GPUNotAvailableError",
+		Message:   fmt.Sprintf("The configured device %s was not found (detected: %s). Choose another device or CPU mode in the settings.", id, detected),
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// historyDBFileName is the SQLite database under the app data directory
+const historyDBFileName = "history.db"
+
+// defaultHistoryLimit is how many items GetHistory returns when the filter sets no limit
+const defaultHistoryLimit = 100
+
+// HistoryItem is one processing run recorded in the history store
+type HistoryItem struct {
+	ID              int64     `json:"id"`
+	JobID           string    `json:"job_id"`
+	InputPath       string    `json:"input_path"`
+	OutputPath      string    `json:"output_path"`
+	Config          string    `json:"config"`
+	Status          string    `json:"status"`
+	ErrorType       string    `json:"error_type,omitempty"`
+	Message         string    `json:"message"`
+	DatabaseID      string    `json:"database_id,omitempty"`
+	DatabasePath    string    `json:"database_path,omitempty"` // analysis database holding DatabaseID
+	DurationSeconds float64   `json:"duration_seconds"`
+	Attempt         int       `json:"attempt"` // 1 for the first run of a job, higher for retries
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+}
+
+// HistoryFilter narrows GetHistory results. Zero values match everything.
+type HistoryFilter struct {
+	Status string `json:"status,omitempty"`
+	// Query matches a substring of the input or output path
+	Query  string     `json:"query,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+	Until  *time.Time `json:"until,omitempty"`
+	Limit  int        `json:"limit,omitempty"`
+	Offset int        `json:"offset,omitempty"`
+}
+
+// HistoryStore persists processing history in SQLite
+type HistoryStore struct {
+	db *sql.DB
+}
+
+// OpenHistoryStore opens or creates the history database at path
+func OpenHistoryStore(path string) (*HistoryStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database %s: %v", path, err)
+	}
+	// SQLite allows a single writer; one connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL,
+		input_path TEXT NOT NULL,
+		output_path TEXT NOT NULL,
+		config TEXT NOT NULL,
+		status TEXT NOT NULL,
+		error_type TEXT NOT NULL DEFAULT '',
+		message TEXT NOT NULL DEFAULT '',
+		database_id TEXT NOT NULL DEFAULT '',
+		duration_seconds REAL NOT NULL DEFAULT 0,
+		started_at INTEGER NOT NULL,
+		finished_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS history_started_at ON history (started_at);`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database %s: %v", path, err)
+	}
+	// Databases created by older versions lack the later columns
+	for _, column := range []string{
+		"attempt INTEGER NOT NULL DEFAULT 1",
+		"database_path TEXT NOT NULL DEFAULT ''",
+	} {
+		_, err = db.Exec("ALTER TABLE history ADD COLUMN " + column)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			db.Close()
+			return nil, fmt.Errorf("failed to upgrade history database %s: %v", path, err)
+		}
+	}
+
+	return &HistoryStore{db: db}, nil
+}
+
+// Close closes the database
+func (s *HistoryStore) Close() error {
+	return s.db.Close()
+}
+
+// Add stores an item and returns its ID
+func (s *HistoryStore) Add(item HistoryItem) (int64, error) {
+	result, err := s.db.Exec(`INSERT INTO history
+		(job_id, input_path, output_path, config, status, error_type, message, database_id, database_path, duration_seconds, attempt, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.JobID, item.InputPath, item.OutputPath, item.Config, item.Status, item.ErrorType, item.Message,
+		item.DatabaseID, item.DatabasePath, item.DurationSeconds, item.Attempt, item.StartedAt.UnixMilli(), item.FinishedAt.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to save history item: %v", err)
+	}
+	return result.LastInsertId()
+}
+
+// historyColumns is the column list matching scanHistoryItem
+const historyColumns = `id, job_id, input_path, output_path, config, status, error_type, message, database_id, database_path, duration_seconds, attempt, started_at, finished_at`
+
+// scanHistoryItem reads one row selected with historyColumns
+func scanHistoryItem(row interface{ Scan(...interface{}) error }) (HistoryItem, error) {
+	var item HistoryItem
+	var startedAt, finishedAt int64
+	err := row.Scan(&item.ID, &item.JobID, &item.InputPath, &item.OutputPath, &item.Config, &item.Status,
+		&item.ErrorType, &item.Message, &item.DatabaseID, &item.DatabasePath, &item.DurationSeconds, &item.Attempt, &startedAt, &finishedAt)
+	if err != nil {
+		return HistoryItem{}, err
+	}
+	item.StartedAt = time.UnixMilli(startedAt)
+	item.FinishedAt = time.UnixMilli(finishedAt)
+	return item, nil
+}
+
+// List returns the items matching filter, newest first
+func (s *HistoryStore) List(filter HistoryFilter) ([]HistoryItem, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Query != "" {
+		conditions = append(conditions, "(instr(input_path, ?) > 0 OR instr(output_path, ?) > 0)")
+		args = append(args, filter.Query, filter.Query)
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, filter.Since.UnixMilli())
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, filter.Until.UnixMilli())
+	}
+
+	query := "SELECT " + historyColumns + " FROM history"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	query += " ORDER BY started_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, filter.Offset)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %v", err)
+	}
+	defer rows.Close()
+
+	items := []HistoryItem{}
+	for rows.Next() {
+		item, err := scanHistoryItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %v", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Get returns the item with the given ID
+func (s *HistoryStore) Get(id int64) (HistoryItem, error) {
+	row := s.db.QueryRow("SELECT "+historyColumns+" FROM history WHERE id = ?", id)
+	item, err := scanHistoryItem(row)
+	if err == sql.ErrNoRows {
+		return HistoryItem{}, fmt.Errorf("history item not found: %d", id)
+	}
+	if err != nil {
+		return HistoryItem{}, fmt.Errorf("failed to read history item %d: %v", id, err)
+	}
+	return item, nil
+}
+
+// LatestSuccessForAnalysis returns the newest successful item that saved the
+// analysis databaseID to databasePath, or sql.ErrNoRows
+func (s *HistoryStore) LatestSuccessForAnalysis(databasePath, databaseID string) (HistoryItem, error) {
+	row := s.db.QueryRow("SELECT "+historyColumns+` FROM history
+		WHERE database_path = ? AND database_id = ? AND status = 'success'
+		ORDER BY started_at DESC, id DESC LIMIT 1`, databasePath, databaseID)
+	item, err := scanHistoryItem(row)
+	if err != nil && err != sql.ErrNoRows {
+		return HistoryItem{}, fmt.Errorf("failed to look up analysis %s in %s: %v", databaseID, databasePath, err)
+	}
+	return item, err
+}
+
+// Delete removes the item with the given ID
+func (s *HistoryStore) Delete(id int64) error {
+	result, err := s.db.Exec("DELETE FROM history WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete history item %d: %v", id, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("history item not found: %d", id)
+	}
+	return nil
+}
+
+// TrimToNewest deletes all but the keepLast most recent items and returns how
+// many were removed
+func (s *HistoryStore) TrimToNewest(keepLast int) (int, error) {
+	result, err := s.db.Exec(`DELETE FROM history WHERE id NOT IN
+		(SELECT id FROM history ORDER BY started_at DESC, id DESC LIMIT ?)`, keepLast)
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim history: %v", err)
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// DeleteBefore deletes the items started before cutoff and returns how many
+// were removed
+func (s *HistoryStore) DeleteBefore(cutoff time.Time) (int, error) {
+	result, err := s.db.Exec("DELETE FROM history WHERE started_at < ?", cutoff.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim history: %v", err)
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// historyStore returns the app's history store, opening it on first use
+func (a *App) historyStore() (*HistoryStore, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.history != nil {
+		return a.history, nil
+	}
+	dir, err := appDataDir()
+	if err != nil {
+		return nil, err
+	}
+	store, err := OpenHistoryStore(filepath.Join(dir, historyDBFileName))
+	if err != nil {
+		return nil, err
+	}
+	a.history = store
+	return store, nil
+}
+
+// recordHistory saves a finished ProcessVideo run. Failures are logged and
+// never affect the processing result.
+func (a *App) recordHistory(request ProcessVideoRequest, response ProcessVideoResponse, startedAt time.Time) {
+	store, err := a.historyStore()
+	if err != nil {
+		a.logger.Warn("failed to open processing history", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	finishedAt := time.Now()
+	config := request.Config
+	if request.AnalysisConfig != nil {
+		if typed, err := request.AnalysisConfig.Marshal(); err == nil {
+			config = typed
+		}
+	}
+	outputPath := request.OutputPath
+	if response.OutputVideoPath != "" {
+		outputPath = response.OutputVideoPath
+	}
+	_, err = store.Add(HistoryItem{
+		JobID:           request.JobID,
+		InputPath:       request.InputPath,
+		OutputPath:      outputPath,
+		Config:          config,
+		Status:          response.Status,
+		ErrorType:       response.ErrorType,
+		Message:         response.Message,
+		DatabaseID:      response.DatabaseID,
+		DatabasePath:    response.DatabasePath,
+		DurationSeconds: finishedAt.Sub(startedAt).Seconds(),
+		Attempt:         max(response.Attempts, 1),
+		StartedAt:       startedAt,
+		FinishedAt:      finishedAt,
+	})
+	if err != nil {
+		a.logger.Warn("failed to record processing history", map[string]interface{}{"job_id": request.JobID, "error": err.Error()})
+	}
+}
+
+// GetHistory returns past processing runs matching filter, newest first
+func (a *App) GetHistory(filter HistoryFilter) ([]HistoryItem, error) {
+	store, err := a.historyStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.List(filter)
+}
+
+// GetHistoryItem returns one past processing run
+func (a *App) GetHistoryItem(id int64) (HistoryItem, error) {
+	store, err := a.historyStore()
+	if err != nil {
+		return HistoryItem{}, err
+	}
+	return store.Get(id)
+}
+
+// DeleteHistoryItem removes a past processing run from the history
+func (a *App) DeleteHistoryItem(id int64) error {
+	store, err := a.historyStore()
+	if err != nil {
+		return err
+	}
+	return store.Delete(id)
+}
+
+// TrimProcessingHistory deletes all but the keepLast most recent runs from the
+// history and returns how many were removed. Each trim is a single SQLite
+// statement, so an interrupted trim leaves the history as it was.
+func (a *App) TrimProcessingHistory(keepLast int) (int, error) {
+	if keepLast < 0 {
+		return 0, fmt.Errorf("keepLast must not be negative, got %d", keepLast)
+	}
+	store, err := a.historyStore()
+	if err != nil {
+		return 0, err
+	}
+	removed, err := store.TrimToNewest(keepLast)
+	if err != nil {
+		return 0, err
+	}
+	a.logger.Info("processing history trimmed", map[string]interface{}{"keep_last": keepLast, "removed": removed})
+	return removed, nil
+}
+
+// SetHistoryRetentionPolicy saves how much history is kept. The policy is
+// applied at every startup: runs older than maxAgeDays are deleted, then all
+// but the newest maxEntries. Zero disables either limit.
+func (a *App) SetHistoryRetentionPolicy(maxEntries int, maxAgeDays int) error {
+	settings := a.GetSettings()
+	settings.HistoryRetention = HistoryRetentionSettings{MaxEntries: maxEntries, MaxAgeDays: maxAgeDays}
+	_, err := a.UpdateSettings(settings)
+	return err
+}
+
+// applyHistoryRetention trims the history to the saved retention policy.
+// Failures are logged; the history is then simply kept until the next startup.
+func (a *App) applyHistoryRetention() {
+	policy := a.GetSettings().HistoryRetention
+	if policy.MaxEntries == 0 && policy.MaxAgeDays == 0 {
+		return
+	}
+	store, err := a.historyStore()
+	if err != nil {
+		a.logger.Warn("failed to open processing history", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	removed := 0
+	if policy.MaxAgeDays > 0 {
+		n, err := store.DeleteBefore(time.Now().AddDate(0, 0, -policy.MaxAgeDays))
+		if err != nil {
+			a.logger.Warn("failed to apply history retention", map[string]interface{}{"error": err.Error()})
+			return
+		}
+		removed += n
+	}
+	if policy.MaxEntries > 0 {
+		n, err := store.TrimToNewest(policy.MaxEntries)
+		if err != nil {
+			a.logger.Warn("failed to apply history retention", map[string]interface{}{"error": err.Error()})
+			return
+		}
+		removed += n
+	}
+	if removed > 0 {
+		a.logger.Info("processing history trimmed", map[string]interface{}{
+			"max_entries":  policy.MaxEntries,
+			"max_age_days": policy.MaxAgeDays,
+			"removed":      removed,
+		})
+	}
+}
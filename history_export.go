@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryExportFilter selects the runs ExportProcessingHistory writes. Zero
+// values match everything; unlike GetHistory, a zero Limit exports every run.
+type HistoryExportFilter struct {
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	StatusFilter []string   `json:"status_filter,omitempty"`
+	Limit        int        `json:"limit,omitempty"`
+}
+
+// ListForExport returns the items matching filter, newest first
+func (s *HistoryStore) ListForExport(filter HistoryExportFilter) ([]HistoryItem, error) {
+	var conditions []string
+	var args []interface{}
+	if len(filter.StatusFilter) > 0 {
+		conditions = append(conditions, "status IN (?"+strings.Repeat(", ?", len(filter.StatusFilter)-1)+")")
+		for _, status := range filter.StatusFilter {
+			args = append(args, status)
+		}
+	}
+	if filter.StartTime != nil {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, filter.StartTime.UnixMilli())
+	}
+	if filter.EndTime != nil {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, filter.EndTime.UnixMilli())
+	}
+
+	query := "SELECT " + historyColumns + " FROM history"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY started_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %v", err)
+	}
+	defer rows.Close()
+
+	items := []HistoryItem{}
+	for rows.Next() {
+		item, err := scanHistoryItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %v", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ExportProcessingHistory writes the runs matching filter to outputPath as
+// "json", "csv" or "html". The HTML report adds a chart of successful and
+// failed runs per day. Unknown formats return an UnsupportedFormat error.
+func (a *App) ExportProcessingHistory(format string, outputPath string, filter HistoryExportFilter) error {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	var render func([]HistoryItem) ([]byte, error)
+	switch format {
+	case "json":
+		render = renderHistoryJSON
+	case "csv":
+		render = renderHistoryCSV
+	case "html":
+		render = renderHistoryHTML
+	default:
+		return fmt.Errorf("UnsupportedFormat: unsupported history format %q, expected json, csv or html", format)
+	}
+	if outputPath == "" {
+		return fmt.Errorf("an output path is required")
+	}
+	absPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("invalid output path %s: %v", outputPath, err)
+	}
+
+	store, err := a.historyStore()
+	if err != nil {
+		return err
+	}
+	items, err := store.ListForExport(filter)
+	if err != nil {
+		return err
+	}
+	data, err := render(items)
+	if err != nil {
+		return fmt.Errorf("failed to render history: %v", err)
+	}
+	if err := writeFileAtomic(absPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history: %v", err)
+	}
+	a.logger.Info("processing history exported", map[string]interface{}{"format": format, "path": absPath, "items": len(items)})
+	return nil
+}
+
+func renderHistoryJSON(items []HistoryItem) ([]byte, error) {
+	return json.MarshalIndent(items, "", "  ")
+}
+
+func renderHistoryCSV(items []HistoryItem) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"id", "job_id", "input_path", "output_path", "status", "error_type", "message",
+		"database_id", "database_path", "duration_seconds", "attempt", "started_at", "finished_at"})
+	for _, item := range items {
+		w.Write([]string{
+			strconv.FormatInt(item.ID, 10),
+			item.JobID,
+			item.InputPath,
+			item.OutputPath,
+			item.Status,
+			item.ErrorType,
+			item.Message,
+			item.DatabaseID,
+			item.DatabasePath,
+			strconv.FormatFloat(item.DurationSeconds, 'f', 3, 64),
+			strconv.Itoa(item.Attempt),
+			item.StartedAt.Format(time.RFC3339),
+			item.FinishedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// historyDay counts the runs started on one day for the HTML chart
+type historyDay struct {
+	Date      string
+	Succeeded int
+	Failed    int
+	// Percentages of the busiest day, which sets the full bar width
+	SucceededWidth float64
+	FailedWidth    float64
+}
+
+// historyDays counts successful and failed runs per local day, oldest first.
+// Runs that neither succeeded nor failed, such as cancelled ones, are not counted.
+func historyDays(items []HistoryItem) []historyDay {
+	byDate := map[string]*historyDay{}
+	for _, item := range items {
+		date := item.StartedAt.Local().Format("2006-01-02")
+		day := byDate[date]
+		if day == nil {
+			day = &historyDay{Date: date}
+			byDate[date] = day
+		}
+		switch item.Status {
+		case "success":
+			day.Succeeded++
+		case "error":
+			day.Failed++
+		}
+	}
+
+	days := make([]historyDay, 0, len(byDate))
+	busiest := 0
+	for _, day := range byDate {
+		days = append(days, *day)
+		busiest = max(busiest, day.Succeeded+day.Failed)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	if busiest > 0 {
+		for i := range days {
+			days[i].SucceededWidth = float64(days[i].Succeeded) / float64(busiest) * 100
+			days[i].FailedWidth = float64(days[i].Failed) / float64(busiest) * 100
+		}
+	}
+	return days
+}
+
+var historyHTMLTemplate = template.Must(template.New("history").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Processing history</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { padding: 4px 8px; text-align: left; border-bottom: 1px solid #ddd; }
+.chart td { border: none; }
+.bar { display: flex; width: 400px; height: 16px; }
+.succeeded { background: #4caf50; }
+.failed { background: #e53935; }
+</style>
+</head>
+<body>
+<h1>Processing history</h1>
+<h2>Runs per day</h2>
+<table class="chart">
+<tr><th>Date</th><th></th><th>Succeeded</th><th>Failed</th></tr>
+{{range .Days}}<tr>
+<td>{{.Date}}</td>
+<td><div class="bar"><div class="succeeded" style="width: {{printf "%.1f" .SucceededWidth}}%"></div><div class="failed" style="width: {{printf "%.1f" .FailedWidth}}%"></div></div></td>
+<td>{{.Succeeded}}</td>
+<td>{{.Failed}}</td>
+</tr>
+{{end}}</table>
+<h2>Runs</h2>
+<table>
+<tr><th>Started</th><th>Input</th><th>Output</th><th>Status</th><th>Duration</th><th>Message</th></tr>
+{{range .Items}}<tr>
+<td>{{.StartedAt.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.InputPath}}</td>
+<td>{{.OutputPath}}</td>
+<td>{{.Status}}</td>
+<td>{{printf "%.1f" .DurationSeconds}}s</td>
+<td>{{.Message}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func renderHistoryHTML(items []HistoryItem) ([]byte, error) {
+	var buf bytes.Buffer
+	err := historyHTMLTemplate.Execute(&buf, struct {
+		Days  []historyDay
+		Items []HistoryItem
+	}{historyDays(items), items})
+	return buf.Bytes(), err
+}
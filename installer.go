@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// installMu prevents two dependency installs from running at once
+var installMu sync.Mutex
+
+// InstallLogLine is the payload of "install:log" events
+type InstallLogLine struct {
+	Stream string `json:"stream"` // "stdout" or "stderr"
+	Line   string `json:"line"`
+}
+
+// InstallResult describes the outcome of InstallBackendDependencies
+type InstallResult struct {
+	Success  bool     `json:"success"`
+	ExitCode int      `json:"exit_code"`
+	Message  string   `json:"message"`
+	Errors   []string `json:"errors"` // error lines reported by uv/pip
+}
+
+// InstallBackendDependencies installs backend/requirements.txt with uv into the
+// backend's virtual environment, creating the environment first if it does not
+// exist, or into the environment of the interpreter chosen in the settings. Output is streamed as "install:log" events and the result
+// is also emitted as "install:completed".
+func (a *App) InstallBackendDependencies() InstallResult {
+	if !installMu.TryLock() {
+		return InstallResult{ExitCode: -1, Message: "An installation is already running.", Errors: []string{}}
+	}
+	defer installMu.Unlock()
+
+	result := a.installBackendDependencies()
+	a.emit("install:completed", result)
+
+	if result.Success {
+		a.logger.Info("backend dependencies installed", nil)
+	} else {
+		a.logger.Error("backend dependency installation failed", map[string]interface{}{
+			"message": result.Message,
+			"errors":  result.Errors,
+		})
+	}
+	return result
+}
+
+// installBackendDependencies runs the install steps in order, stopping at the first failure
+func (a *App) installBackendDependencies() InstallResult {
+	if _, err := exec.LookPath("uv"); err != nil {
+		return InstallResult{ExitCode: -1, Message: "uv was not found on PATH. Install uv first.", Errors: []string{}}
+	}
+
+	backendDir := a.backendDir()
+	if _, err := os.Stat(filepath.Join(backendDir, "requirements.txt")); err != nil {
+		return InstallResult{ExitCode: -1, Message: fmt.Sprintf("Requirements file not found in %s.", backendDir), Errors: []string{}}
+	}
+	if err := a.checkNetworkForOperation(networkOperationInstall); err != nil {
+		return InstallResult{ExitCode: -1, Message: err.Error(), Errors: []string{}}
+	}
+
+	installArgs := []string{"pip", "install", "-r", "requirements.txt"}
+	if a.usesUV() {
+		if _, err := os.Stat(filepath.Join(backendDir, ".venv")); os.IsNotExist(err) {
+			if result := a.runInstallStep(backendDir, "venv"); !result.Success {
+				result.Message = "Failed to create the Python virtual environment. " + result.Message
+				return result
+			}
+		}
+	} else {
+		// Install into the environment of the configured interpreter instead
+		python, _ := a.pythonCommand()
+		installArgs = append(installArgs, "--python", python)
+	}
+
+	result := a.runInstallStep(backendDir, installArgs...)
+	if result.Success {
+		result.Message = "Backend dependencies were installed."
+	} else {
+		result.Message = "Failed to install backend dependencies. " + result.Message
+	}
+	return result
+}
+
+// runInstallStep runs one uv command in dir, streaming and collecting its output
+func (a *App) runInstallStep(dir string, args ...string) InstallResult {
+	cmd := exec.Command("uv", args...)
+	cmd.Dir = dir
+	cmd.Env = a.backendEnv(ProcessVideoRequest{})
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return InstallResult{ExitCode: -1, Message: err.Error(), Errors: []string{}}
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return InstallResult{ExitCode: -1, Message: err.Error(), Errors: []string{}}
+	}
+
+	a.emit("install:log", InstallLogLine{Stream: "stdout", Line: "$ uv " + strings.Join(args, " ")})
+	if err := cmd.Start(); err != nil {
+		return InstallResult{ExitCode: -1, Message: fmt.Sprintf("uv could not be started: %v", err), Errors: []string{}}
+	}
+
+	var mu sync.Mutex
+	errorLines := []string{}
+	var wg sync.WaitGroup
+	stream := func(name string, r io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			a.emit("install:log", InstallLogLine{Stream: name, Line: line})
+			if isInstallErrorLine(line) {
+				mu.Lock()
+				errorLines = append(errorLines, strings.TrimSpace(line))
+				mu.Unlock()
+			}
+		}
+	}
+	wg.Add(2)
+	go stream("stdout", stdout)
+	go stream("stderr", stderr)
+	wg.Wait()
+
+	err = cmd.Wait()
+	result := InstallResult{Success: err == nil, Errors: errorLines}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		result.ExitCode = -1
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("uv %s exited with: %v.", args[0], err)
+	}
+	return result
+}
+
+// isInstallErrorLine reports whether a uv or pip output line describes an error
+func isInstallErrorLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range []string{"error:", "ERROR:", "×"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
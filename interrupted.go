@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InterruptedJob is a job that was running when a previous session ended
+// without finishing it, typically because the application crashed
+type InterruptedJob struct {
+	JobID     string              `json:"job_id"`
+	Request   ProcessVideoRequest `json:"request"`
+	TempPaths []string            `json:"temp_paths"` // partial outputs removed on cleanup
+	StartedAt time.Time           `json:"started_at"`
+	PID       int                 `json:"pid"` // process of the session that ran the job
+}
+
+// jobDescriptorDir returns the directory holding descriptors of in-flight jobs
+func jobDescriptorDir() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "jobs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create job directory %s: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// jobDescriptorPath returns the descriptor file of a job
+func jobDescriptorPath(jobID string) (string, error) {
+	dir, err := jobDescriptorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, jobID+".json"), nil
+}
+
+// persistJobDescriptor records a job and the temporary files it writes before
+// its backend starts. The descriptor is removed when the job returns, so one
+// left on disk at startup belongs to a job that was interrupted.
+func (a *App) persistJobDescriptor(request ProcessVideoRequest, tempPaths ...string) {
+	path, err := jobDescriptorPath(request.JobID)
+	if err == nil {
+		var data []byte
+		data, err = json.MarshalIndent(InterruptedJob{
+			JobID:     request.JobID,
+			Request:   request,
+			TempPaths: tempPaths,
+			StartedAt: time.Now(),
+			PID:       os.Getpid(),
+		}, "", "  ")
+		if err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+	}
+	if err != nil {
+		a.logger.Warn("failed to persist job descriptor", map[string]interface{}{
+			"job_id": request.JobID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// removeJobDescriptor deletes the descriptor of a job that has returned
+func removeJobDescriptor(jobID string) {
+	if path, err := jobDescriptorPath(jobID); err == nil {
+		os.Remove(path)
+	}
+}
+
+// jobRunningElsewhere reports whether a job's descriptor belongs to another
+// process that is still alive
+func jobRunningElsewhere(jobID string) bool {
+	path, err := jobDescriptorPath(jobID)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var job InterruptedJob
+	if json.Unmarshal(data, &job) != nil {
+		return false
+	}
+	return job.PID != os.Getpid() && processAlive(job.PID)
+}
+
+// interruptedJobs reads the descriptors of jobs that are not running, oldest
+// first. Jobs of another live process, such as a second instance or the
+// headless CLI, are still running and are skipped, as are unreadable
+// descriptors. A descriptor whose PID was reused by an unrelated process is
+// reported once that process exits.
+func (a *App) interruptedJobs() ([]InterruptedJob, error) {
+	dir, err := jobDescriptorDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job directory %s: %v", dir, err)
+	}
+
+	a.mu.RLock()
+	running := make(map[string]bool, len(a.jobs))
+	for jobID := range a.jobs {
+		running[jobID] = true
+	}
+	a.mu.RUnlock()
+
+	jobs := []InterruptedJob{}
+	for _, entry := range entries {
+		jobID, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || running[jobID] {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var job InterruptedJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			a.logger.Warn("ignoring unreadable job descriptor", map[string]interface{}{"file": entry.Name(), "error": err.Error()})
+			continue
+		}
+		if job.PID != os.Getpid() && processAlive(job.PID) {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.Before(jobs[j].StartedAt) })
+	return jobs, nil
+}
+
+// staleOutputSlack allows for file systems that store modification times coarsely
+const staleOutputSlack = 2 * time.Second
+
+// removeStaleOutputs deletes the partial outputs of an interrupted job. Files
+// last modified before the job started were not written by it and are kept.
+func removeStaleOutputs(job InterruptedJob) {
+	for _, path := range job.TempPaths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.ModTime().Before(job.StartedAt.Add(-staleOutputSlack)) {
+			continue
+		}
+		os.Remove(path)
+	}
+}
+
+// detectInterruptedJobs cleans up after jobs left behind by a previous session
+// and emits "jobs:interrupted" so the UI can offer to resume them
+func (a *App) detectInterruptedJobs() {
+	jobs, err := a.interruptedJobs()
+	if err != nil {
+		a.logger.Warn("failed to check for interrupted jobs", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	for _, job := range jobs {
+		removeStaleOutputs(job)
+	}
+	a.logger.Warn("found interrupted jobs", map[string]interface{}{"count": len(jobs)})
+	a.emit("jobs:interrupted", jobs)
+}
+
+// GetInterruptedJobs returns the jobs a previous session left unfinished
+func (a *App) GetInterruptedJobs() ([]InterruptedJob, error) {
+	return a.interruptedJobs()
+}
+
+// ResumeInterruptedJobs adds every interrupted job back to the batch queue
+// and returns the new job IDs. A job with a deadline gets the time it had
+// left when it first started, counted from now, since the original deadline
+// has usually passed by the time the app is restarted.
+func (a *App) ResumeInterruptedJobs() ([]string, error) {
+	jobs, err := a.interruptedJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	requests := make([]ProcessVideoRequest, 0, len(jobs))
+	for _, job := range jobs {
+		removeStaleOutputs(job)
+		request := job.Request
+		request.JobID = ""
+		if request.Deadline != nil {
+			request.Deadline = nil
+			if budget := job.Request.Deadline.Sub(job.StartedAt); budget > 0 {
+				deadline := now.Add(budget)
+				request.Deadline = &deadline
+			}
+		}
+		requests = append(requests, request)
+		removeJobDescriptor(job.JobID)
+	}
+	if len(requests) == 0 {
+		return []string{}, nil
+	}
+
+	jobIDs := a.jobQueue().Enqueue(requests)
+	a.logger.Info("resumed interrupted jobs", map[string]interface{}{"count": len(jobIDs)})
+	return jobIDs, nil
+}
+
+// DiscardInterruptedJobs forgets the interrupted jobs without running them again
+func (a *App) DiscardInterruptedJobs() error {
+	jobs, err := a.interruptedJobs()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		removeStaleOutputs(job)
+		removeJobDescriptor(job.JobID)
+	}
+	return nil
+}
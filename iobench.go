@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+)
+
+const (
+	// ioBenchmarkBlockSize is the chunk size used for all benchmark I/O
+	ioBenchmarkBlockSize = 4 * 1024
+	// ioBenchmarkRandomWrites is the maximum number of random 4K writes measured
+	ioBenchmarkRandomWrites = 2000
+	// ioBenchmarkMaxSizeMB caps the size of the benchmark file
+	ioBenchmarkMaxSizeMB = 4096
+	// slowWriteThresholdMBps triggers a performance:io_warning event when sequential writes are slower
+	slowWriteThresholdMBps = 50.0
+)
+
+// IOBenchmarkResult holds the measured throughput of a drive
+type IOBenchmarkResult struct {
+	SequentialWriteMBps float64 `json:"sequential_write_mbps"`
+	SequentialReadMBps  float64 `json:"sequential_read_mbps"`
+	RandomWrite4KIOPS   float64 `json:"random_write_4k_iops"`
+}
+
+// BenchmarkOutputDriveIOPS measures write and read throughput of the drive
+// holding outputDir using a temporary file of testSizeMB megabytes. Reads may
+// be served from the OS page cache and therefore overstate drive speed.
+func (a *App) BenchmarkOutputDriveIOPS(outputDir string, testSizeMB int) (IOBenchmarkResult, error) {
+	if testSizeMB <= 0 || testSizeMB > ioBenchmarkMaxSizeMB {
+		return IOBenchmarkResult{}, fmt.Errorf("test size must be between 1 and %d MB, got %d", ioBenchmarkMaxSizeMB, testSizeMB)
+	}
+	if info, err := os.Stat(outputDir); err != nil || !info.IsDir() {
+		return IOBenchmarkResult{}, fmt.Errorf("output directory is not accessible: %s", outputDir)
+	}
+
+	file, err := os.CreateTemp(outputDir, ".subkoma-iobench-*")
+	if err != nil {
+		return IOBenchmarkResult{}, fmt.Errorf("failed to create benchmark file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	block := make([]byte, ioBenchmarkBlockSize)
+	rand.Read(block)
+	totalBytes := int64(testSizeMB) * 1024 * 1024
+	blocks := totalBytes / ioBenchmarkBlockSize
+	var result IOBenchmarkResult
+
+	// Sequential write, including the flush to disk
+	start := time.Now()
+	for i := int64(0); i < blocks; i++ {
+		if _, err := file.Write(block); err != nil {
+			return IOBenchmarkResult{}, fmt.Errorf("benchmark write failed: %v", err)
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return IOBenchmarkResult{}, fmt.Errorf("benchmark sync failed: %v", err)
+	}
+	result.SequentialWriteMBps = megabytesPerSecond(totalBytes, time.Since(start))
+
+	// Sequential read
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return IOBenchmarkResult{}, fmt.Errorf("benchmark seek failed: %v", err)
+	}
+	start = time.Now()
+	for {
+		_, err := io.ReadFull(file, block)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return IOBenchmarkResult{}, fmt.Errorf("benchmark read failed: %v", err)
+		}
+	}
+	result.SequentialReadMBps = megabytesPerSecond(totalBytes, time.Since(start))
+
+	// Random 4K writes, each flushed so the drive rather than the cache is measured
+	writes := int64(ioBenchmarkRandomWrites)
+	if blocks < writes {
+		writes = blocks
+	}
+	start = time.Now()
+	for i := int64(0); i < writes; i++ {
+		offset := rand.Int63n(blocks) * ioBenchmarkBlockSize
+		if _, err := file.WriteAt(block, offset); err != nil {
+			return IOBenchmarkResult{}, fmt.Errorf("benchmark random write failed: %v", err)
+		}
+		if err := file.Sync(); err != nil {
+			return IOBenchmarkResult{}, fmt.Errorf("benchmark sync failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+		result.RandomWrite4KIOPS = float64(writes) / elapsed
+	}
+
+	if result.SequentialWriteMBps < slowWriteThresholdMBps {
+		a.emit("performance:io_warning", map[string]interface{}{
+			"output_dir":            outputDir,
+			"sequential_write_mbps": result.SequentialWriteMBps,
+			"threshold_mbps":        slowWriteThresholdMBps,
+		})
+	}
+
+	return result, nil
+}
+
+// megabytesPerSecond converts a byte count and duration to MB/s
+func megabytesPerSecond(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) / (1024 * 1024) / elapsed.Seconds()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runningJob is a backend process started for a ProcessVideo request
+type runningJob struct {
+	stop       func() error // terminates the process; nil until it has started
+	outputPath string
+	cancelled  bool
+}
+
+// registerJob records a job before its backend process starts, so it can be
+// cancelled at any point of its run
+func (a *App) registerJob(jobID, outputPath string) *runningJob {
+	job := &runningJob{outputPath: outputPath}
+
+	a.mu.Lock()
+	if a.jobs == nil {
+		a.jobs = make(map[string]*runningJob)
+	}
+	a.jobs[jobID] = job
+	a.mu.Unlock()
+
+	return job
+}
+
+// unregisterJob forgets a finished job
+func (a *App) unregisterJob(jobID string) {
+	a.mu.Lock()
+	delete(a.jobs, jobID)
+	a.mu.Unlock()
+}
+
+// attachJobProcess stores how to stop the started backend process of a job. A
+// job that was cancelled while starting is terminated right away.
+func (a *App) attachJobProcess(job *runningJob, stop func() error) {
+	a.mu.Lock()
+	job.stop = stop
+	cancelled := job.cancelled
+	a.mu.Unlock()
+
+	if cancelled {
+		_ = stop()
+	}
+}
+
+// isJobCancelled reports whether CancelProcessing was called for the job
+func (a *App) isJobCancelled(job *runningJob) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return job.cancelled
+}
+
+// CancelProcessing stops a running ProcessVideo job. The backend process and
+// its children are terminated and the partial output file is removed by the
+// job itself, which then returns a "cancelled" status to its caller. Jobs still
+// waiting in the batch queue are removed from it.
+func (a *App) CancelProcessing(jobID string) ProcessVideoResponse {
+	a.mu.Lock()
+	job, ok := a.jobs[jobID]
+	if ok {
+		job.cancelled = true
+	}
+	queue := a.queue
+	a.mu.Unlock()
+
+	if !ok && queue != nil && queue.CancelPending(jobID) {
+		a.logger.Info("queued job cancelled", map[string]interface{}{"job_id": jobID})
+		return ProcessVideoResponse{
+			Status:  "cancelled",
+			JobID:   jobID,
+			Message: "The queued job was cancelled before it started.",
+		}
+	}
+
+	if !ok {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "JobNotFoundError",
+			JobID:     jobID,
+			Message:   fmt.Sprintf("No running job with ID %s. It may have already finished.", jobID),
+		}
+	}
+
+	a.mu.RLock()
+	stop := job.stop
+	a.mu.RUnlock()
+	if stop != nil {
+		if err := stop(); err != nil {
+			a.logger.Warn("failed to terminate backend process", map[string]interface{}{
+				"job_id": jobID,
+				"error":  err.Error(),
+			})
+		}
+	}
+
+	a.logger.Info("job cancellation requested", map[string]interface{}{"job_id": jobID})
+	a.emit("processing:cancelled", jobID)
+
+	return ProcessVideoResponse{
+		Status:  "cancelled",
+		JobID:   jobID,
+		Message: "Processing was cancelled.",
+	}
+}
+
+// cancelledResponse cleans up after a cancelled job and builds its response
+func (a *App) cancelledResponse(jobID string, job *runningJob) ProcessVideoResponse {
+	if job.outputPath != "" {
+		if err := os.Remove(job.outputPath); err != nil && !os.IsNotExist(err) {
+			a.logger.Warn("failed to remove partial output", map[string]interface{}{
+				"job_id":      jobID,
+				"output_path": job.outputPath,
+				"error":       err.Error(),
+			})
+		}
+	}
+
+	return ProcessVideoResponse{
+		Status:  "cancelled",
+		JobID:   jobID,
+		Message: "Processing was cancelled and the partial output was removed.",
+	}
+}
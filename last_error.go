@@ -0,0 +1,54 @@
+package main
+
+import "time"
+
+// ProcessingErrorRecord describes the most recent failed processing attempt
+type ProcessingErrorRecord struct {
+	ErrorType  string    `json:"error_type"`
+	Message    string    `json:"message"`
+	InputPath  string    `json:"input_path"`
+	OccurredAt time.Time `json:"occurred_at"`
+	JobID      string    `json:"job_id,omitempty"`
+}
+
+// recordProcessingResult remembers a failed response, or clears the last
+// error when processing succeeded
+func (a *App) recordProcessingResult(request ProcessVideoRequest, response ProcessVideoResponse) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if response.Status == "success" {
+		a.lastError = nil
+		return
+	}
+	if response.Status == "cancelled" || response.Status == "skipped" {
+		return
+	}
+	a.lastError = &ProcessingErrorRecord{
+		ErrorType:  response.ErrorType,
+		Message:    response.Message,
+		InputPath:  request.InputPath,
+		OccurredAt: time.Now(),
+		JobID:      request.JobID,
+	}
+}
+
+// GetLastProcessingError returns the most recent processing error, or nil if
+// the last run succeeded or the error was dismissed
+func (a *App) GetLastProcessingError() *ProcessingErrorRecord {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.lastError == nil {
+		return nil
+	}
+	record := *a.lastError
+	return &record
+}
+
+// ClearLastProcessingError dismisses the stored processing error
+func (a *App) ClearLastProcessingError() {
+	a.mu.Lock()
+	a.lastError = nil
+	a.mu.Unlock()
+}
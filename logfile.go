@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+const (
+	// logFileName is the active log file under the log directory
+	logFileName = "subkoma.log"
+	// maxLogFileSize is the size at which the active log file is rotated
+	maxLogFileSize = 5 * 1024 * 1024
+	// maxLogBackups is how many rotated files (subkoma.log.1 ... .N) are kept
+	maxLogBackups = 5
+	// maxStderrExcerpt is how much backend stderr is copied into a log entry
+	maxStderrExcerpt = 4000
+)
+
+// logDir returns the directory holding the application log files
+func logDir() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory %s: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// rotatingFile is an io.Writer that appends to a file and rotates it once it
+// grows past maxSize, keeping a fixed number of numbered backups
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// newRotatingFile opens path for appending
+func newRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the active file and records its current size
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %v", r.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file %s: %v", r.path, err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would push the file past maxSize
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups up by one and starts a new active file
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(r.path + "." + strconv.Itoa(r.backups))
+	for i := r.backups - 1; i >= 1; i-- {
+		os.Rename(r.path+"."+strconv.Itoa(i), r.path+"."+strconv.Itoa(i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.open()
+}
+
+// Close closes the active file
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// stderrExcerpt keeps the end of backend stderr, where tracebacks and the
+// error JSON are written
+func stderrExcerpt(stderr string) string {
+	if len(stderr) <= maxStderrExcerpt {
+		return stderr
+	}
+	return "..." + stderr[len(stderr)-maxStderrExcerpt:]
+}
+
+// OpenLogFolder opens the log directory in the system file manager
+func (a *App) OpenLogFolder() error {
+	dir, err := logDir()
+	if err != nil {
+		return err
+	}
+	return openWithSystem(dir)
+}
+
+// GetRecentLogs returns up to n of the newest log entries, oldest first.
+// Entries are read from the active file and then from rotated backups.
+func (a *App) GetRecentLogs(n int) ([]map[string]interface{}, error) {
+	if n <= 0 {
+		return []map[string]interface{}{}, nil
+	}
+	dir, err := logDir()
+	if err != nil {
+		return nil, err
+	}
+
+	base := filepath.Join(dir, logFileName)
+	var newestFirst []map[string]interface{}
+	for i := 0; i <= maxLogBackups && len(newestFirst) < n; i++ {
+		path := base
+		if i > 0 {
+			path = base + "." + strconv.Itoa(i)
+		}
+		entries, err := readLogEntries(path)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for j := len(entries) - 1; j >= 0 && len(newestFirst) < n; j-- {
+			newestFirst = append(newestFirst, entries[j])
+		}
+	}
+
+	entries := make([]map[string]interface{}, len(newestFirst))
+	for i, entry := range newestFirst {
+		entries[len(newestFirst)-1-i] = entry
+	}
+	return entries, nil
+}
+
+// readLogEntries parses a JSON-lines log file, skipping lines that are not JSON
+func readLogEntries(path string) ([]map[string]interface{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// openWithSystem opens a file or folder with the platform's default handler
+func openWithSystem(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("explorer", path)
+	case "darwin":
+		cmd = exec.Command("open", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	// The handler may keep running; reap it in the background
+	go cmd.Wait()
+	return nil
+}
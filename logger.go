@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Logger writes structured log entries. Fields carry the machine-readable
+// context (paths, error types, stderr) that would otherwise be folded into strings.
+type Logger interface {
+	Debug(msg string, fields map[string]interface{})
+	Info(msg string, fields map[string]interface{})
+	Warn(msg string, fields map[string]interface{})
+	Error(msg string, fields map[string]interface{})
+}
+
+// jsonLogger writes one JSON object per line
+type jsonLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLogger returns a Logger that writes JSON lines to w
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{w: w}
+}
+
+func (l *jsonLogger) Debug(msg string, fields map[string]interface{}) {
+	l.write("DEBUG", msg, fields)
+}
+
+func (l *jsonLogger) Info(msg string, fields map[string]interface{}) {
+	l.write("INFO", msg, fields)
+}
+
+func (l *jsonLogger) Warn(msg string, fields map[string]interface{}) {
+	l.write("WARN", msg, fields)
+}
+
+func (l *jsonLogger) Error(msg string, fields map[string]interface{}) {
+	l.write("ERROR", msg, fields)
+}
+
+func (l *jsonLogger) write(level string, msg string, fields map[string]interface{}) {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for key, value := range fields {
+		entry[key] = value
+	}
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = level
+	entry["msg"] = msg
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(append(line, '\n'))
+}
+
+// NoopLogger discards all log entries
+type NoopLogger struct{}
+
+func (NoopLogger) Debug(string, map[string]interface{}) {}
+func (NoopLogger) Info(string, map[string]interface{})  {}
+func (NoopLogger) Warn(string, map[string]interface{})  {}
+func (NoopLogger) Error(string, map[string]interface{}) {}
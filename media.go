@@ -0,0 +1,539 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// supportedFrameRates lists the target frame rates accepted by ConvertFrameRate
+var supportedFrameRates = []float64{23.976, 24.0, 25.0, 29.97, 30.0, 50.0, 59.94, 60.0}
+
+// frameRateTolerance is the maximum difference at which two frame rates are considered equal
+const frameRateTolerance = 0.01
+
+// runFFprobe runs ffprobe with the given arguments and returns its stdout
+func runFFprobe(args ...string) ([]byte, error) {
+	cmd := exec.Command("ffprobe", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// runFFmpeg runs ffmpeg with the given arguments, returning stderr in the error on failure
+func runFFmpeg(args ...string) error {
+	cmd := exec.Command("ffmpeg", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// probeFrameRate returns the average frame rate of the first video stream
+func probeFrameRate(path string) (float64, error) {
+	out, err := runFFprobe(
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=avg_frame_rate",
+		"-of", "json",
+		path,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var probe struct {
+		Streams []struct {
+			AvgFrameRate string `json:"avg_frame_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse ffprobe output: %v", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, fmt.Errorf("no video stream found in %s", path)
+	}
+
+	return parseFrameRate(probe.Streams[0].AvgFrameRate)
+}
+
+// parseFrameRate parses an ffprobe rational such as "30000/1001" or a plain number
+func parseFrameRate(s string) (float64, error) {
+	num, den, found := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid frame rate %q", s)
+	}
+	if !found {
+		return n, nil
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0, fmt.Errorf("invalid frame rate %q", s)
+	}
+	return n / d, nil
+}
+
+// ConvertFrameRate re-encodes a video to one of the supported frame rates and
+// returns the frame rate of the resulting file. It returns the rate as well as
+// an error, rather than only an error, so callers see the rate achieved. The
+// output must be a different file than the input.
+func (a *App) ConvertFrameRate(inputPath string, outputPath string, targetFPS float64) (float64, error) {
+	supported := false
+	for _, fps := range supportedFrameRates {
+		if math.Abs(fps-targetFPS) < frameRateTolerance {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return 0, fmt.Errorf("unsupported target frame rate %g, expected one of %v", targetFPS, supportedFrameRates)
+	}
+
+	if _, err := os.Stat(inputPath); err != nil {
+		return 0, fmt.Errorf("cannot access input video file: %s. Error: %v", inputPath, err)
+	}
+	if sameFile(inputPath, outputPath) {
+		return 0, fmt.Errorf("output path %s is the input video; choose another file", outputPath)
+	}
+
+	currentFPS, err := probeFrameRate(inputPath)
+	if err != nil {
+		return 0, err
+	}
+
+	// Nothing to convert, so copy the file instead of re-encoding it
+	if math.Abs(currentFPS-targetFPS) < frameRateTolerance {
+		if err := copyOutputFile(inputPath, outputPath, generateID()); err != nil {
+			return 0, fmt.Errorf("failed to copy %s to %s: %v", inputPath, outputPath, err)
+		}
+		a.emit("convert:skipped", map[string]interface{}{
+			"input_path":  inputPath,
+			"output_path": outputPath,
+			"fps":         currentFPS,
+		})
+		return currentFPS, nil
+	}
+
+	if err := runFFmpeg("-y", "-i", inputPath, "-vf", fmt.Sprintf("fps=%g", targetFPS), outputPath); err != nil {
+		return 0, err
+	}
+
+	return probeFrameRate(outputPath)
+}
+
+// sameFile reports whether two paths name the same file, either literally or
+// through a link
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA == nil && errB == nil && absA == absB {
+		return true
+	}
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// defaultAudioBitrate is assumed for the audio track when estimating output sizes
+const defaultAudioBitrate = 128000
+
+// referenceBitrates720p is the video bitrate (bits/s) that gives acceptable
+// quality at 1280x720 for each codec. Other resolutions scale by pixel count.
+var referenceBitrates720p = map[string]float64{
+	"h264": 2000000,
+	"h265": 1200000,
+	"hevc": 1200000,
+	"vp9":  1300000,
+	"av1":  1000000,
+}
+
+// candidateHeights are the output heights considered by ComputeOptimalResolution, largest first
+var candidateHeights = []int{2160, 1440, 1080, 720, 480, 360, 240}
+
+// OptimalResolution is the largest output resolution that fits a size budget
+type OptimalResolution struct {
+	Width            int     `json:"width"`
+	Height           int     `json:"height"`
+	EstimatedBitrate string  `json:"estimated_bitrate"`
+	EstimatedSizeMB  float64 `json:"estimated_size_mb"`
+}
+
+// probeDuration returns the container duration in seconds
+func probeDuration(path string) (float64, error) {
+	out, err := runFFprobe("-v", "error", "-show_entries", "format=duration", "-of", "json", path)
+	if err != nil {
+		return 0, err
+	}
+
+	var probe struct {
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse ffprobe output: %v", err)
+	}
+
+	duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("could not determine duration of %s", path)
+	}
+	return duration, nil
+}
+
+// ComputeOptimalResolution picks the highest resolution whose estimated
+// bitrate for codec keeps the output within targetSizeBytes. The source
+// aspect ratio is preserved and the source resolution is never exceeded.
+func (a *App) ComputeOptimalResolution(inputPath string, targetSizeBytes int64, codec string) (OptimalResolution, error) {
+	codec = strings.ToLower(codec)
+	referenceBitrate, ok := referenceBitrates720p[codec]
+	if !ok {
+		return OptimalResolution{}, fmt.Errorf("unsupported codec %q", codec)
+	}
+	if targetSizeBytes <= 0 {
+		return OptimalResolution{}, fmt.Errorf("target size must be positive, got %d", targetSizeBytes)
+	}
+
+	duration, err := probeDuration(inputPath)
+	if err != nil {
+		return OptimalResolution{}, err
+	}
+	streams, err := probeStreams(inputPath)
+	if err != nil {
+		return OptimalResolution{}, err
+	}
+
+	var sourceWidth, sourceHeight int
+	audioBitrate := 0.0
+	for _, stream := range streams {
+		switch stream.CodecType {
+		case "video":
+			if sourceHeight == 0 {
+				sourceWidth, sourceHeight = stream.Width, stream.Height
+			}
+		case "audio":
+			audioBitrate = defaultAudioBitrate
+		}
+	}
+	if sourceWidth <= 0 || sourceHeight <= 0 {
+		return OptimalResolution{}, fmt.Errorf("no video stream found in %s", inputPath)
+	}
+
+	// targetSizeBytes = (videoBitrate + audioBitrate) * duration / 8
+	videoBitrate := float64(targetSizeBytes)*8/duration - audioBitrate
+	if videoBitrate <= 0 {
+		return OptimalResolution{}, fmt.Errorf("target size of %d bytes is too small for a %.1f second video", targetSizeBytes, duration)
+	}
+
+	aspect := float64(sourceWidth) / float64(sourceHeight)
+	chosenHeight := 0
+	for _, height := range candidateHeights {
+		if height > sourceHeight {
+			continue
+		}
+		width := evenDimension(float64(height) * aspect)
+		required := referenceBitrate * float64(width*height) / (1280 * 720)
+		if required <= videoBitrate {
+			chosenHeight = height
+			break
+		}
+	}
+	if chosenHeight == 0 {
+		chosenHeight = candidateHeights[len(candidateHeights)-1]
+		if chosenHeight > sourceHeight {
+			chosenHeight = sourceHeight
+		}
+	}
+
+	sizeBytes := (videoBitrate + audioBitrate) * duration / 8
+	return OptimalResolution{
+		Width:            evenDimension(float64(chosenHeight) * aspect),
+		Height:           chosenHeight,
+		EstimatedBitrate: fmt.Sprintf("%dk", int(videoBitrate/1000)),
+		EstimatedSizeMB:  sizeBytes / (1024 * 1024),
+	}, nil
+}
+
+// evenDimension rounds a frame dimension to the nearest even number, as most encoders require
+func evenDimension(v float64) int {
+	return int(math.Round(v/2)) * 2
+}
+
+// probeRotation returns the clockwise display rotation (0, 90, 180 or 270)
+// of the first video stream, read from the rotate tag or the display matrix
+func probeRotation(path string) (int, error) {
+	streams, err := probeStreams(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, stream := range streams {
+		if stream.CodecType == "video" {
+			return streamRotation(stream)
+		}
+	}
+
+	return 0, fmt.Errorf("no video stream found in %s", path)
+}
+
+// streamRotation returns the clockwise display rotation of a video stream
+func streamRotation(stream ffprobeStream) (int, error) {
+	rotation := 0
+	if tag, ok := stream.Tags["rotate"]; ok {
+		var err error
+		rotation, err = strconv.Atoi(tag)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rotate tag %q", tag)
+		}
+	} else {
+		// The display matrix stores the counter-clockwise angle
+		for _, sideData := range stream.SideDataList {
+			if sideData.Rotation != 0 {
+				rotation = -int(math.Round(sideData.Rotation))
+				break
+			}
+		}
+	}
+	return ((rotation % 360) + 360) % 360, nil
+}
+
+// NormalizeRotation re-encodes a video whose rotation is only stored as
+// metadata so the frames themselves are upright, and clears the rotation.
+// It returns false when the video needs no rotation.
+func (a *App) NormalizeRotation(inputPath string, outputPath string) (bool, error) {
+	if _, err := os.Stat(inputPath); err != nil {
+		return false, fmt.Errorf("cannot access input video file: %s. Error: %v", inputPath, err)
+	}
+
+	rotation, err := probeRotation(inputPath)
+	if err != nil {
+		return false, err
+	}
+	switch rotation {
+	case 0:
+		return false, nil
+	case 90, 180, 270:
+	default:
+		return false, fmt.Errorf("unsupported rotation of %d degrees", rotation)
+	}
+
+	// ffmpeg's autorotation turns the frames upright and leaves the display
+	// matrix out of the output. Rotating by hand with autorotation disabled
+	// would copy the matrix, so players would rotate the frames a second time.
+	// Clearing the rotate tag covers ffmpeg versions that still copy it.
+	err = runFFmpeg(
+		"-y",
+		"-i", inputPath,
+		"-metadata:s:v:0", "rotate=0",
+		"-c:a", "copy",
+		outputPath,
+	)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DecodingValidationResult summarizes a sampled decode of a video
+type DecodingValidationResult struct {
+	DecodedFrames int  `json:"decoded_frames"`
+	ErrorFrames   int  `json:"error_frames"`
+	CorruptFrames int  `json:"corrupt_frames"`
+	IsValid       bool `json:"is_valid"`
+}
+
+// ValidateOutputDecoding decodes sampleCount evenly spaced frames with ffmpeg
+// and counts the frames that produced decoder errors
+func (a *App) ValidateOutputDecoding(videoPath string, sampleCount int) (DecodingValidationResult, error) {
+	if sampleCount <= 0 {
+		return DecodingValidationResult{}, fmt.Errorf("sample count must be positive, got %d", sampleCount)
+	}
+	if _, err := os.Stat(videoPath); err != nil {
+		return DecodingValidationResult{}, fmt.Errorf("cannot access video file: %s. Error: %v", videoPath, err)
+	}
+
+	totalFrames, err := probeFrameCount(videoPath)
+	if err != nil {
+		return DecodingValidationResult{}, err
+	}
+	stride := totalFrames / sampleCount
+	if stride < 1 {
+		stride = 1
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-v", "error",
+		"-progress", "pipe:1",
+		"-i", videoPath,
+		"-vf", fmt.Sprintf(`select=not(mod(n\,%d))`, stride),
+		"-vsync", "0",
+		"-frames:v", strconv.Itoa(sampleCount),
+		"-f", "null", "-",
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return DecodingValidationResult{}, fmt.Errorf("ffmpeg failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result DecodingValidationResult
+
+	// -progress reports cumulative counters; the last frame= line is the total
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "frame="); ok {
+			if n, err := strconv.Atoi(value); err == nil {
+				result.DecodedFrames = n
+			}
+		}
+	}
+
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if contains(line, "corrupt") || contains(line, "concealing") {
+			result.CorruptFrames++
+		} else {
+			result.ErrorFrames++
+		}
+	}
+
+	result.IsValid = result.DecodedFrames > 0 && result.ErrorFrames == 0 && result.CorruptFrames == 0
+	return result, nil
+}
+
+// probeFrameCount returns the number of frames in the first video stream,
+// estimating it from duration and frame rate when the container does not store it
+func probeFrameCount(path string) (int, error) {
+	streams, err := probeStreams(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, stream := range streams {
+		if stream.CodecType != "video" {
+			continue
+		}
+		if n, err := strconv.Atoi(stream.NbFrames); err == nil && n > 0 {
+			return n, nil
+		}
+
+		duration, err := probeDuration(path)
+		if err != nil {
+			return 0, err
+		}
+		fps, err := parseFrameRate(stream.AvgFrameRate)
+		if err != nil {
+			return 0, err
+		}
+		return int(duration * fps), nil
+	}
+
+	return 0, fmt.Errorf("no video stream found in %s", path)
+}
+
+// TimeRange is a span of media time in seconds
+type TimeRange struct {
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+}
+
+// SilenceReport lists the silent parts of an audio track
+type SilenceReport struct {
+	HasSilentSegments  bool        `json:"has_silent_segments"`
+	SilentSegments     []TimeRange `json:"silent_segments"`
+	TotalSilentSeconds float64     `json:"total_silent_seconds"`
+	IsTotallySilent    bool        `json:"is_totally_silent"`
+}
+
+// silenceTolerance is the slack allowed when deciding whether silence covers the whole track
+const silenceTolerance = 0.1
+
+// DetectAudioSilence runs ffmpeg's silencedetect filter over the first audio
+// track and reports the segments quieter than silenceThresholdDb for at least
+// minDurationSeconds
+func (a *App) DetectAudioSilence(inputPath string, silenceThresholdDb float64, minDurationSeconds float64) (SilenceReport, error) {
+	if minDurationSeconds <= 0 {
+		return SilenceReport{}, fmt.Errorf("minimum silence duration must be positive, got %g", minDurationSeconds)
+	}
+	if _, err := os.Stat(inputPath); err != nil {
+		return SilenceReport{}, fmt.Errorf("cannot access input video file: %s. Error: %v", inputPath, err)
+	}
+
+	streams, err := probeStreams(inputPath)
+	if err != nil {
+		return SilenceReport{}, err
+	}
+	hasAudio := false
+	for _, stream := range streams {
+		if stream.CodecType == "audio" {
+			hasAudio = true
+			break
+		}
+	}
+	if !hasAudio {
+		return SilenceReport{}, fmt.Errorf("no audio stream found in %s", inputPath)
+	}
+
+	duration, err := probeDuration(inputPath)
+	if err != nil {
+		return SilenceReport{}, err
+	}
+
+	// silencedetect reports at info level, so the log level must not be lowered
+	cmd := exec.Command("ffmpeg",
+		"-hide_banner", "-nostats",
+		"-i", inputPath,
+		"-vn",
+		"-af", fmt.Sprintf("silencedetect=n=%gdB:d=%g", silenceThresholdDb, minDurationSeconds),
+		"-f", "null", "-",
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return SilenceReport{}, fmt.Errorf("ffmpeg failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	report := SilenceReport{SilentSegments: []TimeRange{}}
+	openStart := -1.0
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		if _, value, ok := strings.Cut(line, "silence_start: "); ok {
+			if start, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				openStart = math.Max(start, 0)
+			}
+		} else if _, value, ok := strings.Cut(line, "silence_end: "); ok && openStart >= 0 {
+			endField, _, _ := strings.Cut(value, "|")
+			if end, err := strconv.ParseFloat(strings.TrimSpace(endField), 64); err == nil {
+				report.SilentSegments = append(report.SilentSegments, TimeRange{StartSeconds: openStart, EndSeconds: end})
+				openStart = -1
+			}
+		}
+	}
+	// Silence that runs to the end of the file has no silence_end line
+	if openStart >= 0 {
+		report.SilentSegments = append(report.SilentSegments, TimeRange{StartSeconds: openStart, EndSeconds: duration})
+	}
+
+	for _, segment := range report.SilentSegments {
+		report.TotalSilentSeconds += segment.EndSeconds - segment.StartSeconds
+	}
+	report.HasSilentSegments = len(report.SilentSegments) > 0
+	report.IsTotallySilent = report.HasSilentSegments && report.TotalSilentSeconds >= duration-silenceTolerance
+
+	return report, nil
+}
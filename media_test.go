@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// writeRotatedFixture encodes a 64x32 test clip and tags it with a display
+// rotation, the way phones store portrait video
+func writeRotatedFixture(t *testing.T, dir string, rotation int) string {
+	t.Helper()
+	plain := filepath.Join(dir, "plain.mp4")
+	if err := runFFmpeg("-y", "-f", "lavfi", "-i", "testsrc=size=64x32:duration=0.5:rate=10",
+		"-c:v", "mpeg4", plain); err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+
+	rotated := filepath.Join(dir, "rotated.mp4")
+	// -display_rotation takes the counter-clockwise angle; older ffmpeg only has the rotate tag
+	err := runFFmpeg("-y", "-display_rotation", strconv.Itoa(-rotation), "-i", plain, "-c", "copy", rotated)
+	if err != nil {
+		err = runFFmpeg("-y", "-i", plain, "-c", "copy", "-metadata:s:v:0", "rotate="+strconv.Itoa(rotation), rotated)
+	}
+	if err != nil {
+		t.Fatalf("failed to tag fixture rotation: %v", err)
+	}
+	return rotated
+}
+
+func TestNormalizeRotation(t *testing.T) {
+	for _, tool := range []string{"ffmpeg", "ffprobe"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not found", tool)
+		}
+	}
+	app := NewAppWithLogger(NewJSONLogger(io.Discard))
+
+	tests := []struct {
+		name       string
+		rotation   int
+		wantWidth  int
+		wantHeight int
+	}{
+		{name: "90 degrees", rotation: 90, wantWidth: 32, wantHeight: 64},
+		{name: "180 degrees", rotation: 180, wantWidth: 64, wantHeight: 32},
+		{name: "270 degrees", rotation: 270, wantWidth: 32, wantHeight: 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			input := writeRotatedFixture(t, dir, tt.rotation)
+			if got, err := probeRotation(input); err != nil || got != tt.rotation {
+				t.Fatalf("fixture rotation = %d, %v, want %d", got, err, tt.rotation)
+			}
+
+			output := filepath.Join(dir, "upright.mp4")
+			rotated, err := app.NormalizeRotation(input, output)
+			if err != nil || !rotated {
+				t.Fatalf("NormalizeRotation() = %v, %v, want true, nil", rotated, err)
+			}
+
+			// Any rotation left in the output would make players turn it again
+			if got, err := probeRotation(output); err != nil || got != 0 {
+				t.Errorf("output rotation = %d, %v, want 0", got, err)
+			}
+			streams, err := probeStreams(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, stream := range streams {
+				if stream.CodecType == "video" && (stream.Width != tt.wantWidth || stream.Height != tt.wantHeight) {
+					t.Errorf("output size = %dx%d, want %dx%d", stream.Width, stream.Height, tt.wantWidth, tt.wantHeight)
+				}
+			}
+		})
+	}
+
+	t.Run("upright input", func(t *testing.T) {
+		dir := t.TempDir()
+		input := filepath.Join(dir, "plain.mp4")
+		if err := runFFmpeg("-y", "-f", "lavfi", "-i", "testsrc=size=64x32:duration=0.5:rate=10", "-c:v", "mpeg4", input); err != nil {
+			t.Fatal(err)
+		}
+		if rotated, err := app.NormalizeRotation(input, filepath.Join(dir, "out.mp4")); err != nil || rotated {
+			t.Errorf("NormalizeRotation() = %v, %v, want false, nil", rotated, err)
+		}
+	})
+}
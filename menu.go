@@ -0,0 +1,77 @@
+package main
+
+import (
+	goruntime "runtime"
+
+	"github.com/wailsapp/wails/v2/pkg/menu"
+	"github.com/wailsapp/wails/v2/pkg/menu/keys"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SetupApplicationMenu installs the native application menu. Actions that
+// need state held by the frontend (such as the analysis config) are
+// forwarded as "menu:*" events for the UI to handle.
+func (a *App) SetupApplicationMenu() error {
+	if err := a.WaitForContext(contextReadyTimeout); err != nil {
+		return err
+	}
+
+	appMenu := menu.NewMenu()
+	if goruntime.GOOS == "darwin" {
+		appMenu.Append(menu.AppMenu())
+		appMenu.Append(menu.EditMenu())
+	}
+
+	fileMenu := appMenu.AddSubmenu("File")
+	fileMenu.AddText("Open Video...", keys.CmdOrCtrl("o"), func(_ *menu.CallbackData) {
+		// Dialogs block, so keep them off the menu callback
+		go a.openVideoFromMenu()
+	})
+	fileMenu.AddSeparator()
+	// On macOS the app menu already binds Cmd+Q to Quit
+	var quitKey *keys.Accelerator
+	if goruntime.GOOS != "darwin" {
+		quitKey = keys.CmdOrCtrl("q")
+	}
+	fileMenu.AddText("Quit", quitKey, func(_ *menu.CallbackData) {
+		runtime.Quit(a.ctx)
+	})
+
+	processMenu := appMenu.AddSubmenu("Process")
+	processMenu.AddText("Process Video", keys.CmdOrCtrl("r"), func(_ *menu.CallbackData) {
+		a.emit("menu:process-video", nil)
+	})
+
+	helpMenu := appMenu.AddSubmenu("Help")
+	helpMenu.AddText("About", nil, func(_ *menu.CallbackData) {
+		go a.showAboutDialog()
+	})
+
+	runtime.MenuSetApplicationMenu(a.ctx, appMenu)
+	return nil
+}
+
+// openVideoFromMenu shows the video file dialog and hands the selection to the frontend
+func (a *App) openVideoFromMenu() {
+	path, err := a.SelectVideoFile()
+	if err != nil {
+		a.logger.Warn("failed to open video from menu", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if path == "" {
+		return
+	}
+	a.emit("menu:video-selected", map[string]interface{}{"path": path})
+}
+
+// showAboutDialog shows basic information about the application
+func (a *App) showAboutDialog() {
+	_, err := runtime.MessageDialog(a.ctx, runtime.MessageDialogOptions{
+		Type:    runtime.InfoDialog,
+		Title:   "About subkoma",
+		Message: "subkoma analyzes motion in video and retimes frames for an animation-style result.",
+	})
+	if err != nil {
+		a.logger.Warn("failed to show about dialog", map[string]interface{}{"error": err.Error()})
+	}
+}
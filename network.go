@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// networkDialTimeout bounds each connectivity probe
+const networkDialTimeout = 2 * time.Second
+
+// networkOperationInstall is the operation name checked before
+// InstallBackendDependencies downloads packages
+const networkOperationInstall = "install_dependencies"
+
+// CheckNetworkConnectivity attempts a TCP connection to each "host:port"
+// entry in parallel and reports which ones are reachable
+func (a *App) CheckNetworkConnectivity(hosts []string) (map[string]bool, error) {
+	if err := validateNetworkHosts(hosts); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	reachable := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			ok := false
+			if conn, err := net.DialTimeout("tcp", host, networkDialTimeout); err == nil {
+				conn.Close()
+				ok = true
+			}
+			mu.Lock()
+			reachable[host] = ok
+			mu.Unlock()
+		}(host)
+	}
+	wg.Wait()
+
+	return reachable, nil
+}
+
+// validateNetworkHosts checks that every entry has the form "host:port"
+func validateNetworkHosts(hosts []string) error {
+	for _, host := range hosts {
+		name, port, err := net.SplitHostPort(host)
+		if err != nil {
+			return fmt.Errorf("invalid host %q, expected host:port: %v", host, err)
+		}
+		if name == "" || port == "" {
+			return fmt.Errorf("invalid host %q, expected host:port", host)
+		}
+	}
+	return nil
+}
+
+// SetNetworkRequiredForOperation registers the hosts that must be reachable
+// before the named network operation runs. An empty list removes the
+// requirement. The only operation is "install_dependencies", checked by
+// InstallBackendDependencies.
+func (a *App) SetNetworkRequiredForOperation(operation string, hosts []string) error {
+	if operation != networkOperationInstall {
+		return fmt.Errorf("unknown network operation %q, expected %s", operation, networkOperationInstall)
+	}
+	if err := validateNetworkHosts(hosts); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(hosts) == 0 {
+		delete(a.networkRequirements, operation)
+		return nil
+	}
+	if a.networkRequirements == nil {
+		a.networkRequirements = make(map[string][]string)
+	}
+	a.networkRequirements[operation] = append([]string(nil), hosts...)
+	return nil
+}
+
+// checkNetworkForOperation verifies the hosts registered for an operation.
+// Network operations call this first and report the "NetworkUnavailable"
+// error it returns.
+func (a *App) checkNetworkForOperation(operation string) error {
+	a.mu.RLock()
+	hosts := a.networkRequirements[operation]
+	a.mu.RUnlock()
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	reachable, err := a.CheckNetworkConnectivity(hosts)
+	if err != nil {
+		return err
+	}
+
+	var unreachable []string
+	for host, ok := range reachable {
+		if !ok {
+			unreachable = append(unreachable, host)
+		}
+	}
+	if len(unreachable) > 0 {
+		sort.Strings(unreachable)
+		return fmt.Errorf("NetworkUnavailable: %s requires %s, which could not be reached", operation, strings.Join(unreachable, ", "))
+	}
+	return nil
+}
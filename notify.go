@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// sendNotification shows a desktop notification with the platform's native mechanism
+func sendNotification(title, message string) error {
+	cmd := notificationCommand(title, message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show notification: %v: %s", err, out)
+	}
+	return nil
+}
+
+// notifyJobFinished shows a notification for a finished job when notifications
+// are enabled. Cancelled and skipped jobs are not announced; nothing was processed.
+func (a *App) notifyJobFinished(request ProcessVideoRequest, response ProcessVideoResponse) {
+	if !a.GetNotificationsEnabled() || response.Status == "cancelled" || response.Status == "skipped" {
+		return
+	}
+
+	name := filepath.Base(request.InputPath)
+	title := "Processing finished"
+	message := fmt.Sprintf("%s was processed successfully.", name)
+	if response.Status != "success" {
+		title = "Processing failed"
+		message = fmt.Sprintf("%s could not be processed: %s", name, response.Message)
+	}
+
+	if err := sendNotification(title, message); err != nil {
+		a.logger.Warn("failed to show notification", map[string]interface{}{
+			"job_id": request.JobID,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// GetNotificationsEnabled reports whether a notification is shown when a job finishes
+func (a *App) GetNotificationsEnabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.config.NotifyOnCompletion
+}
+
+// SetNotificationsEnabled turns the job completion notification on or off and saves it in the settings
+func (a *App) SetNotificationsEnabled(enabled bool) error {
+	settings := a.GetSettings()
+	settings.NotifyOnCompletion = enabled
+	_, err := a.UpdateSettings(settings)
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build darwin
+
+package main
+
+import "os/exec"
+
+// notificationCommand builds an osascript command that shows a notification.
+// The text is passed as script arguments so it is never parsed as AppleScript.
+func notificationCommand(title, message string) *exec.Cmd {
+	return exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+		"-e", "end run",
+		title, message)
+}
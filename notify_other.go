@@ -0,0 +1,10 @@
+//go:build !windows && !darwin
+
+package main
+
+import "os/exec"
+
+// notificationCommand builds a notify-send command that shows a notification
+func notificationCommand(title, message string) *exec.Cmd {
+	return exec.Command("notify-send", "--app-name=subkoma", title, message)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Output collision policies for ProcessVideoRequest.OverwritePolicy
+const (
+	overwritePolicyFail       = "fail"        // refuse to run when the output exists
+	overwritePolicyOverwrite  = "overwrite"   // replace the existing output (the default)
+	overwritePolicyAutoRename = "auto-rename" // write to "name (2).mp4" and so on instead
+	overwritePolicySkip       = "skip"        // keep the existing output and do not run
+)
+
+// overwritePolicies lists the accepted values of ProcessVideoRequest.OverwritePolicy
+var overwritePolicies = []string{overwritePolicyFail, overwritePolicyOverwrite, overwritePolicyAutoRename, overwritePolicySkip}
+
+// maxAutoRenameAttempts bounds the numbered names tried by the auto-rename policy
+const maxAutoRenameAttempts = 10000
+
+// applyOverwritePolicy enforces the request's policy for an output path that
+// already exists. It returns the request to run, with the output path changed
+// for auto-rename, or a response when the job must not run. The claimed output
+// path must be released with releaseOutputPath once the job has returned.
+func (a *App) applyOverwritePolicy(request ProcessVideoRequest) (ProcessVideoRequest, *ProcessVideoResponse) {
+	policy := request.OverwritePolicy
+	if policy == "" {
+		policy = overwritePolicyOverwrite
+	}
+	if !containsString(overwritePolicies, policy) {
+		return request, &ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "ValidationError",
+			Message:   fmt.Sprintf("Unknown overwrite policy %q. Use one of: %s.", policy, strings.Join(overwritePolicies, ", ")),
+		}
+	}
+	if request.OutputPath == "" || policy == overwritePolicyOverwrite {
+		return request, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.outputTaken(request.OutputPath) {
+		a.claimOutputPath(request.OutputPath)
+		return request, nil
+	}
+
+	switch policy {
+	case overwritePolicyFail:
+		return request, &ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "OutputExistsError",
+			Message:   fmt.Sprintf("Output file already exists: %s. Please choose another path or allow overwriting.", request.OutputPath),
+		}
+	case overwritePolicySkip:
+		return request, &ProcessVideoResponse{
+			Status:          "skipped",
+			OutputVideoPath: request.OutputPath,
+			Message:         fmt.Sprintf("Output file already exists: %s. The job was skipped.", request.OutputPath),
+		}
+	}
+
+	ext := filepath.Ext(request.OutputPath)
+	stem := strings.TrimSuffix(request.OutputPath, ext)
+	for n := 2; n <= maxAutoRenameAttempts; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", stem, n, ext)
+		if !a.outputTaken(candidate) {
+			a.claimOutputPath(candidate)
+			request.OutputPath = candidate
+			return request, nil
+		}
+	}
+	return request, &ProcessVideoResponse{
+		Status:    "error",
+		ErrorType: "OutputExistsError",
+		Message:   fmt.Sprintf("No free file name found next to %s. Please choose another path.", request.OutputPath),
+	}
+}
+
+// outputTaken reports whether path exists on disk or is claimed by a running
+// job that has not written it yet; a.mu must be held
+func (a *App) outputTaken(path string) bool {
+	if a.claimedOutputs[path] {
+		return true
+	}
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// claimOutputPath reserves path for a job; a.mu must be held
+func (a *App) claimOutputPath(path string) {
+	if a.claimedOutputs == nil {
+		a.claimedOutputs = make(map[string]bool)
+	}
+	a.claimedOutputs[path] = true
+}
+
+// releaseOutputPath drops the claim taken by applyOverwritePolicy
+func (a *App) releaseOutputPath(path string) {
+	a.mu.Lock()
+	delete(a.claimedOutputs, path)
+	a.mu.Unlock()
+}
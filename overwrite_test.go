@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyOverwritePolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		existing  []string // files present in the output folder
+		claimed   []string // outputs reserved by running jobs
+		wantPath  string
+		wantError string // ErrorType of the early response
+		wantSkip  bool
+	}{
+		{name: "free path is kept", policy: overwritePolicyAutoRename, wantPath: "out.mp4"},
+		{name: "default overwrites", policy: "", existing: []string{"out.mp4"}, wantPath: "out.mp4"},
+		{name: "overwrite keeps path", policy: overwritePolicyOverwrite, existing: []string{"out.mp4"}, wantPath: "out.mp4"},
+		{name: "fail on existing", policy: overwritePolicyFail, existing: []string{"out.mp4"}, wantError: "OutputExistsError"},
+		{name: "skip on existing", policy: overwritePolicySkip, existing: []string{"out.mp4"}, wantSkip: true},
+		{name: "auto-rename to (2)", policy: overwritePolicyAutoRename, existing: []string{"out.mp4"}, wantPath: "out (2).mp4"},
+		{name: "auto-rename past taken numbers", policy: overwritePolicyAutoRename, existing: []string{"out.mp4", "out (2).mp4", "out (3).mp4"}, wantPath: "out (4).mp4"},
+		{name: "auto-rename around a claimed output", policy: overwritePolicyAutoRename, existing: []string{"out.mp4"}, claimed: []string{"out (2).mp4"}, wantPath: "out (3).mp4"},
+		{name: "claimed output counts as existing", policy: overwritePolicyFail, claimed: []string{"out.mp4"}, wantError: "OutputExistsError"},
+		{name: "unknown policy", policy: "replace", wantError: "ValidationError"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			app := NewAppWithLogger(NewJSONLogger(io.Discard))
+			for _, name := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, name := range tt.claimed {
+				app.claimOutputPath(filepath.Join(dir, name))
+			}
+
+			request := ProcessVideoRequest{OutputPath: filepath.Join(dir, "out.mp4"), OverwritePolicy: tt.policy}
+			got, early := app.applyOverwritePolicy(request)
+
+			switch {
+			case tt.wantError != "":
+				if early == nil || early.Status != "error" || early.ErrorType != tt.wantError {
+					t.Errorf("applyOverwritePolicy() response = %+v, want error %s", early, tt.wantError)
+				}
+			case tt.wantSkip:
+				if early == nil || early.Status != "skipped" || early.OutputVideoPath != request.OutputPath {
+					t.Errorf("applyOverwritePolicy() response = %+v, want skipped", early)
+				}
+			default:
+				if early != nil {
+					t.Fatalf("applyOverwritePolicy() response = %+v, want the job to run", early)
+				}
+				if want := filepath.Join(dir, tt.wantPath); got.OutputPath != want {
+					t.Errorf("output path = %q, want %q", got.OutputPath, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoRenameClaimsOutputs(t *testing.T) {
+	dir := t.TempDir()
+	app := NewAppWithLogger(NewJSONLogger(io.Discard))
+	output := filepath.Join(dir, "out.mp4")
+	if err := os.WriteFile(output, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	request := ProcessVideoRequest{OutputPath: output, OverwritePolicy: overwritePolicyAutoRename}
+
+	// Two jobs started before either writes its output must not share a name
+	first, _ := app.applyOverwritePolicy(request)
+	second, _ := app.applyOverwritePolicy(request)
+	if first.OutputPath != filepath.Join(dir, "out (2).mp4") || second.OutputPath != filepath.Join(dir, "out (3).mp4") {
+		t.Fatalf("output paths = %q, %q, want out (2).mp4 and out (3).mp4", first.OutputPath, second.OutputPath)
+	}
+
+	// Once the first job returns, its name is free again
+	app.releaseOutputPath(first.OutputPath)
+	if _, claimed := app.claimedOutputs[first.OutputPath]; claimed {
+		t.Errorf("%s still claimed after release", first.OutputPath)
+	}
+	third, _ := app.applyOverwritePolicy(request)
+	if third.OutputPath != first.OutputPath {
+		t.Errorf("output path = %q, want the released %q", third.OutputPath, first.OutputPath)
+	}
+}
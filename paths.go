@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appDataDirName is the directory under the user config dir that holds subkoma's files
+const appDataDirName = "subkoma"
+
+// appDataDir returns the per-user data directory, creating it if needed
+func appDataDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %v", err)
+	}
+
+	dir := filepath.Join(base, appDataDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create app data directory %s: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// appCacheDir returns the per-user cache directory for the named kind of
+// data, creating it if needed
+func appCacheDir(kind string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %v", err)
+	}
+
+	dir := filepath.Join(base, appDataDirName, kind)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory %s: %v", dir, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import "fmt"
+
+// ConditionalStage is a pipeline step that only runs when Condition accepts
+// the response of the most recently executed stage. A nil Condition always
+// runs; conditions can only be set from Go, so stages sent by the frontend always run.
+type ConditionalStage struct {
+	Condition func(ProcessVideoResponse) bool `json:"-"`
+	Request   ProcessVideoRequest             `json:"request"`
+}
+
+// PipelineStageResult is the outcome of one stage of a conditional pipeline
+type PipelineStageResult struct {
+	StageIndex int                  `json:"stage_index"`
+	Status     string               `json:"status"` // "executed" or "skipped"
+	Response   ProcessVideoResponse `json:"response"`
+}
+
+// ExecuteConditionalPipeline runs the stages in order. Before each stage its
+// condition is evaluated against the last executed stage's response (an empty
+// response for the first stage); stages whose condition is false are skipped.
+func (a *App) ExecuteConditionalPipeline(stages []ConditionalStage) ([]PipelineStageResult, error) {
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("pipeline has no stages")
+	}
+
+	results := make([]PipelineStageResult, 0, len(stages))
+	var previous ProcessVideoResponse
+	for i, stage := range stages {
+		if stage.Condition != nil && !stage.Condition(previous) {
+			results = append(results, PipelineStageResult{StageIndex: i, Status: "skipped"})
+			continue
+		}
+
+		previous = a.ProcessVideo(stage.Request)
+		results = append(results, PipelineStageResult{StageIndex: i, Status: "executed", Response: previous})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ConfigFieldDiff is one analysis parameter that differs between two configs
+type ConfigFieldDiff struct {
+	Key     string      `json:"key"` // dotted path, e.g. "motion_weights.velocity"
+	Active  interface{} `json:"active"`
+	Profile interface{} `json:"profile"`
+}
+
+// ConfigDiffResult is the result of CompareActiveConfigToProfile
+type ConfigDiffResult struct {
+	ProfileName string            `json:"profile_name"`
+	Equal       bool              `json:"equal"`
+	Diffs       []ConfigFieldDiff `json:"diffs"` // sorted by key
+}
+
+// flattenConfig returns the parameters of config keyed by dotted JSON path
+func flattenConfig(config AnalysisConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	flat := make(map[string]interface{})
+	flattenJSON("", tree, flat)
+	return flat, nil
+}
+
+// flattenJSON adds the leaves of a decoded JSON object to flat, joining
+// nested keys with dots
+func flattenJSON(prefix string, tree map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenJSON(key, nested, flat)
+		} else {
+			flat[key] = value
+		}
+	}
+}
+
+// CompareActiveConfigToProfile lists the parameters in which activeConfig, a
+// JSON config as passed to ProcessVideo, differs from the named preset.
+// Parameters missing from activeConfig take their defaults, as they would
+// when processing.
+func (a *App) CompareActiveConfigToProfile(activeConfig string, profileName string) (ConfigDiffResult, error) {
+	active, err := ParseAnalysisConfig(activeConfig)
+	if err != nil {
+		return ConfigDiffResult{}, fmt.Errorf("invalid active configuration: %v", err)
+	}
+	profile, err := a.LoadPreset(profileName)
+	if err != nil {
+		return ConfigDiffResult{}, err
+	}
+
+	activeFields, err := flattenConfig(active)
+	if err != nil {
+		return ConfigDiffResult{}, fmt.Errorf("failed to encode active configuration: %v", err)
+	}
+	profileFields, err := flattenConfig(profile)
+	if err != nil {
+		return ConfigDiffResult{}, fmt.Errorf("failed to encode preset %s: %v", profileName, err)
+	}
+
+	result := ConfigDiffResult{ProfileName: profileName, Diffs: []ConfigFieldDiff{}}
+	for key, value := range activeFields {
+		if !reflect.DeepEqual(value, profileFields[key]) {
+			result.Diffs = append(result.Diffs, ConfigFieldDiff{Key: key, Active: value, Profile: profileFields[key]})
+		}
+	}
+	sort.Slice(result.Diffs, func(i, j int) bool { return result.Diffs[i].Key < result.Diffs[j].Key })
+	result.Equal = len(result.Diffs) == 0
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// envPrefixPattern restricts ExportProfileAsEnvVars prefixes to valid environment variable names
+var envPrefixPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ExportProfileAsEnvVars returns the parameters of the named preset as
+// environment variables for CI pipelines. Nested keys are joined with
+// underscores and upper-cased after the prefix, so threshold_high becomes
+// PREFIX_THRESHOLD_HIGH and motion_weights.velocity PREFIX_MOTION_WEIGHTS_VELOCITY.
+// An empty prefix leaves the names unprefixed.
+func (a *App) ExportProfileAsEnvVars(profileName string, prefix string) (map[string]string, error) {
+	if prefix != "" && !envPrefixPattern.MatchString(prefix) {
+		return nil, fmt.Errorf("invalid prefix %q: use letters, digits and '_', not starting with a digit", prefix)
+	}
+	config, err := a.LoadPreset(profileName)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := flattenConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preset %s: %v", profileName, err)
+	}
+
+	vars := make(map[string]string, len(fields))
+	for key, value := range fields {
+		name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if prefix != "" {
+			name = strings.ToUpper(prefix) + "_" + name
+		}
+		vars[name] = envValue(value)
+	}
+	return vars, nil
+}
+
+// envValue formats a decoded JSON value as an environment variable value
+func envValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// presetNamePattern restricts preset names to characters that are safe in file names
+var presetNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.-]{0,63}$`)
+
+// Preset is a named set of analysis parameters stored as a JSON file
+type Preset struct {
+	Name      string         `json:"name"`
+	Config    AnalysisConfig `json:"config"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// presetsDir returns the directory holding preset files, creating it if needed
+func presetsDir() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "presets")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("cannot create presets directory %s: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// presetPath returns the file that stores the named preset
+func presetPath(name string) (string, error) {
+	if !presetNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid preset name %q: use up to 64 letters, digits, spaces, '.', '_' or '-', starting with a letter or digit", name)
+	}
+	dir, err := presetsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// readPreset loads and validates a preset file
+func readPreset(path string) (Preset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Preset{}, err
+	}
+
+	// Missing parameters in hand-edited or shared files keep their defaults
+	preset := Preset{Config: DefaultAnalysisConfig()}
+	if err := json.Unmarshal(data, &preset); err != nil {
+		return Preset{}, fmt.Errorf("failed to parse preset %s: %v", path, err)
+	}
+	if err := preset.Config.Validate(); err != nil {
+		return Preset{}, fmt.Errorf("preset %s has invalid parameters: %v", path, err)
+	}
+	if preset.Name == "" {
+		preset.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return preset, nil
+}
+
+// SavePreset stores config under name, replacing any preset with that name
+func (a *App) SavePreset(name string, config AnalysisConfig) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid analysis parameters: %v", err)
+	}
+	path, err := presetPath(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(Preset{Name: name, Config: config, UpdatedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode preset %s: %v", name, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save preset %s: %v", name, err)
+	}
+	a.auditPreset(configAuditSave, name, &config)
+	return nil
+}
+
+// ListPresets returns all readable presets, sorted by name. Files that fail
+// to parse or validate are skipped with a warning.
+func (a *App) ListPresets() ([]Preset, error) {
+	dir, err := presetsDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presets: %v", err)
+	}
+
+	presets := []Preset{}
+	for _, path := range paths {
+		preset, err := readPreset(path)
+		if err != nil {
+			a.logger.Warn("skipping unreadable preset", map[string]interface{}{"path": path, "error": err.Error()})
+			continue
+		}
+		presets = append(presets, preset)
+	}
+
+	sort.Slice(presets, func(i, j int) bool {
+		return strings.ToLower(presets[i].Name) < strings.ToLower(presets[j].Name)
+	})
+	return presets, nil
+}
+
+// LoadPreset returns the analysis parameters stored under name
+func (a *App) LoadPreset(name string) (AnalysisConfig, error) {
+	path, err := presetPath(name)
+	if err != nil {
+		return AnalysisConfig{}, err
+	}
+	preset, err := readPreset(path)
+	if os.IsNotExist(err) {
+		return AnalysisConfig{}, fmt.Errorf("preset not found: %s", name)
+	}
+	if err != nil {
+		return AnalysisConfig{}, err
+	}
+	a.auditPreset(configAuditLoad, name, &preset.Config)
+	return preset.Config, nil
+}
+
+// DeletePreset removes the named preset
+func (a *App) DeletePreset(name string) error {
+	path, err := presetPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return fmt.Errorf("preset not found: %s", name)
+	} else if err != nil {
+		return fmt.Errorf("failed to delete preset %s: %v", name, err)
+	}
+	a.auditPreset(configAuditDelete, name, nil)
+	return nil
+}
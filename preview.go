@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// previewOutputPath returns where the preview of a run writing outputPath is
+// saved: "<name>_preview<ext>" next to the full output
+func previewOutputPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "_preview" + ext
+}
+
+// ProcessVideoWithPreviewValidation processes the first previewSeconds of the
+// request's segment and returns the preview response with two functions.
+// confirmFn adds the full run to the batch queue and returns its pending
+// response; cancelFn deletes the preview output. Each function acts only on
+// its first call; a second confirmFn call returns an error response. The
+// function results cannot cross into the frontend, so this is for Go callers.
+func (a *App) ProcessVideoWithPreviewValidation(request ProcessVideoRequest, previewSeconds float64) (ProcessVideoResponse, func() ProcessVideoResponse, func()) {
+	var confirmOnce, cancelOnce sync.Once
+	confirmFn := func() ProcessVideoResponse {
+		response := ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "PreviewError",
+			Message:   "The full run of this preview was already started.",
+		}
+		confirmOnce.Do(func() {
+			jobID := a.EnqueueVideos([]ProcessVideoRequest{request})[0]
+			response = ProcessVideoResponse{
+				Status:  jobStatePending,
+				JobID:   jobID,
+				Message: "The full run was added to the queue.",
+			}
+		})
+		return response
+	}
+
+	if previewSeconds <= 0 {
+		return ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "ValidationError",
+			Message:   fmt.Sprintf("Preview length must be positive, got %gs.", previewSeconds),
+		}, confirmFn, func() {}
+	}
+
+	preview := request
+	preview.JobID = ""
+	preview.OutputPath = previewOutputPath(request.OutputPath)
+	preview.Description = "Preview of " + jobDescription(request)
+	preview.FallbackConfig = ""
+	preview.EndTime = request.StartTime + previewSeconds
+	if request.EndTime > 0 && request.EndTime < preview.EndTime {
+		preview.EndTime = request.EndTime
+	}
+	// A preview longer than the video covers all of it
+	if duration, err := probeDuration(request.InputPath); err == nil && preview.EndTime >= duration {
+		preview.EndTime = 0
+	}
+
+	response := a.ProcessVideo(preview)
+	cancelFn := func() {
+		cancelOnce.Do(func() {
+			if response.Status != "success" || response.OutputVideoPath == "" {
+				return
+			}
+			a.unwatchOutputPath(response.OutputVideoPath)
+			if err := os.Remove(response.OutputVideoPath); err != nil && !os.IsNotExist(err) {
+				a.logger.Warn("failed to remove preview output", map[string]interface{}{
+					"job_id": response.JobID,
+					"path":   response.OutputVideoPath,
+					"error":  err.Error(),
+				})
+			}
+		})
+	}
+	return response, confirmFn, cancelFn
+}
@@ -0,0 +1,501 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// maxFrameTimestamps caps the number of entries returned by ExtractFrameTimestamps
+const maxFrameTimestamps = 500000
+
+// FrameTimestamp maps a video frame to its packet timestamps
+type FrameTimestamp struct {
+	FrameNumber             int   `json:"frame_number"`
+	PresentationTimestampMs int64 `json:"presentation_timestamp_ms"`
+	// DisplayTimestampMs is taken from the packet's decode timestamp (dts)
+	DisplayTimestampMs int64 `json:"display_timestamp_ms"`
+}
+
+// ExtractFrameTimestamps reads the packet timestamps of the first video stream.
+// The ffprobe output is consumed line by line and capped at maxFrameTimestamps entries.
+func (a *App) ExtractFrameTimestamps(inputPath string) ([]FrameTimestamp, error) {
+	if _, err := os.Stat(inputPath); err != nil {
+		return nil, fmt.Errorf("cannot access input video file: %s. Error: %v", inputPath, err)
+	}
+
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-select_streams", "v",
+		"-show_packets",
+		"-show_entries", "packet=pts_time,dts_time,pos",
+		"-of", "csv=p=0",
+		inputPath,
+	)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to ffprobe output: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffprobe: %v", err)
+	}
+
+	timestamps := make([]FrameTimestamp, 0, 1024)
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if len(timestamps) >= maxFrameTimestamps {
+			// Stop reading; the remaining packets are intentionally dropped
+			_ = cmd.Process.Kill()
+			break
+		}
+
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ",")
+		if len(fields) < 2 {
+			continue
+		}
+		pts, ptsOK := parseTimestampMs(fields[0])
+		dts, dtsOK := parseTimestampMs(fields[1])
+		if !ptsOK && !dtsOK {
+			continue
+		}
+		if !ptsOK {
+			pts = dts
+		}
+		if !dtsOK {
+			dts = pts
+		}
+
+		timestamps = append(timestamps, FrameTimestamp{
+			FrameNumber:             len(timestamps),
+			PresentationTimestampMs: pts,
+			DisplayTimestampMs:      dts,
+		})
+	}
+	scanErr := scanner.Err()
+	waitErr := cmd.Wait()
+
+	if len(timestamps) >= maxFrameTimestamps {
+		return timestamps, nil
+	}
+	if scanErr != nil {
+		return nil, fmt.Errorf("failed to read ffprobe output: %v", scanErr)
+	}
+	if waitErr != nil {
+		return nil, fmt.Errorf("ffprobe failed: %v", waitErr)
+	}
+
+	return timestamps, nil
+}
+
+// parseTimestampMs converts an ffprobe time in seconds to milliseconds.
+// It reports false for missing values such as "N/A".
+func parseTimestampMs(s string) (int64, bool) {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int64(math.Round(seconds * 1000)), true
+}
+
+// avSyncThresholdMs is the largest audio/video offset still considered in sync
+const avSyncThresholdMs = 80.0
+
+// ffprobeStream is the subset of an ffprobe stream entry used by the probe methods
+type ffprobeStream struct {
+	Index     int    `json:"index"`
+	CodecType string `json:"codec_type"`
+	CodecName string `json:"codec_name"`
+	StartTime string `json:"start_time"`
+	Duration  string `json:"duration"`
+	BitRate   string `json:"bit_rate"`
+
+	AvgFrameRate string `json:"avg_frame_rate"`
+	NbFrames     string `json:"nb_frames"`
+
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	SampleRate string `json:"sample_rate"`
+	Channels   int    `json:"channels"`
+
+	Disposition struct {
+		Default int `json:"default"`
+		Forced  int `json:"forced"`
+	} `json:"disposition"`
+
+	Tags         map[string]string `json:"tags"`
+	SideDataList []struct {
+		Rotation float64 `json:"rotation"`
+	} `json:"side_data_list"`
+}
+
+// probeStreams returns all streams of a media file as reported by ffprobe
+func probeStreams(path string) ([]ffprobeStream, error) {
+	out, err := runFFprobe("-v", "error", "-show_streams", "-of", "json", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe struct {
+		Streams []ffprobeStream `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %v", err)
+	}
+	return probe.Streams, nil
+}
+
+// AVSyncReport describes the measured offset between the audio and video streams
+type AVSyncReport struct {
+	OffsetMs          float64 `json:"offset_ms"`
+	IsInSync          bool    `json:"is_in_sync"`
+	MeasurementMethod string  `json:"measurement_method"`
+}
+
+// CheckAudioVideoSync compares the start times and end times of the first
+// audio and video streams. OffsetMs is the larger of the two differences, with
+// positive values meaning the audio lags behind the video.
+func (a *App) CheckAudioVideoSync(videoPath string) (AVSyncReport, error) {
+	if _, err := os.Stat(videoPath); err != nil {
+		return AVSyncReport{}, fmt.Errorf("cannot access video file: %s. Error: %v", videoPath, err)
+	}
+
+	streams, err := probeStreams(videoPath)
+	if err != nil {
+		return AVSyncReport{}, err
+	}
+
+	var video, audio *ffprobeStream
+	for i := range streams {
+		switch streams[i].CodecType {
+		case "video":
+			if video == nil {
+				video = &streams[i]
+			}
+		case "audio":
+			if audio == nil {
+				audio = &streams[i]
+			}
+		}
+	}
+	if video == nil || audio == nil {
+		return AVSyncReport{}, fmt.Errorf("%s needs both a video and an audio stream to check sync", videoPath)
+	}
+
+	videoStart, _ := strconv.ParseFloat(video.StartTime, 64)
+	audioStart, _ := strconv.ParseFloat(audio.StartTime, 64)
+	offsetMs := (audioStart - videoStart) * 1000
+
+	// Compare stream ends as well, since drift accumulates over the duration
+	videoDuration, videoErr := strconv.ParseFloat(video.Duration, 64)
+	audioDuration, audioErr := strconv.ParseFloat(audio.Duration, 64)
+	if videoErr == nil && audioErr == nil {
+		endOffsetMs := (audioStart + audioDuration - videoStart - videoDuration) * 1000
+		if math.Abs(endOffsetMs) > math.Abs(offsetMs) {
+			offsetMs = endOffsetMs
+		}
+	}
+
+	return AVSyncReport{
+		OffsetMs:          offsetMs,
+		IsInSync:          math.Abs(offsetMs) <= avSyncThresholdMs,
+		MeasurementMethod: "ffprobe_stream_timing",
+	}, nil
+}
+
+// ContainerInfo describes the container format of a media file
+type ContainerInfo struct {
+	FormatName         string            `json:"format_name"`
+	FormatLongName     string            `json:"format_long_name"`
+	OverallBitrateKbps int               `json:"overall_bitrate_kbps"`
+	NumStreams         int               `json:"num_streams"`
+	Tags               map[string]string `json:"tags"`
+	StartTime          float64           `json:"start_time"`
+	Duration           float64           `json:"duration"`
+}
+
+// GetVideoContainerInfo returns container-level metadata from ffprobe's format section
+func (a *App) GetVideoContainerInfo(inputPath string) (ContainerInfo, error) {
+	if _, err := os.Stat(inputPath); err != nil {
+		return ContainerInfo{}, fmt.Errorf("cannot access input video file: %s. Error: %v", inputPath, err)
+	}
+
+	out, err := runFFprobe("-v", "error", "-show_format", "-of", "json", inputPath)
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+
+	var probe struct {
+		Format struct {
+			FormatName     string            `json:"format_name"`
+			FormatLongName string            `json:"format_long_name"`
+			BitRate        string            `json:"bit_rate"`
+			NbStreams      int               `json:"nb_streams"`
+			Tags           map[string]string `json:"tags"`
+			StartTime      string            `json:"start_time"`
+			Duration       string            `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return ContainerInfo{}, fmt.Errorf("failed to parse ffprobe output: %v", err)
+	}
+
+	// Numeric fields are reported as strings and may be missing for some containers
+	bitrate, _ := strconv.ParseFloat(probe.Format.BitRate, 64)
+	startTime, _ := strconv.ParseFloat(probe.Format.StartTime, 64)
+	duration, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+
+	tags := probe.Format.Tags
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	return ContainerInfo{
+		FormatName:         probe.Format.FormatName,
+		FormatLongName:     probe.Format.FormatLongName,
+		OverallBitrateKbps: int(bitrate / 1000),
+		NumStreams:         probe.Format.NbStreams,
+		Tags:               tags,
+		StartTime:          startTime,
+		Duration:           duration,
+	}, nil
+}
+
+// StreamInfo describes a single stream of a media file. Width and Height are
+// only set for video streams, SampleRate and Channels only for audio streams.
+type StreamInfo struct {
+	Index     int    `json:"index"`
+	CodecType string `json:"codec_type"`
+	CodecName string `json:"codec_name"`
+	Language  string `json:"language"`
+	BitRate   int    `json:"bit_rate"`
+	IsDefault bool   `json:"is_default"`
+	IsForced  bool   `json:"is_forced"`
+
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	SampleRate int `json:"sample_rate,omitempty"`
+	Channels   int `json:"channels,omitempty"`
+}
+
+// GetVideoStreamList enumerates the video, audio, subtitle and data streams of a file
+func (a *App) GetVideoStreamList(inputPath string) ([]StreamInfo, error) {
+	if _, err := os.Stat(inputPath); err != nil {
+		return nil, fmt.Errorf("cannot access input video file: %s. Error: %v", inputPath, err)
+	}
+
+	streams, err := probeStreams(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]StreamInfo, 0, len(streams))
+	for _, stream := range streams {
+		bitRate, _ := strconv.Atoi(stream.BitRate)
+		info := StreamInfo{
+			Index:     stream.Index,
+			CodecType: stream.CodecType,
+			CodecName: stream.CodecName,
+			Language:  stream.Tags["language"],
+			BitRate:   bitRate,
+			IsDefault: stream.Disposition.Default == 1,
+			IsForced:  stream.Disposition.Forced == 1,
+		}
+
+		switch stream.CodecType {
+		case "video":
+			info.Width = stream.Width
+			info.Height = stream.Height
+		case "audio":
+			info.SampleRate, _ = strconv.Atoi(stream.SampleRate)
+			info.Channels = stream.Channels
+		}
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// ChapterMark is a chapter stored in a media file
+type ChapterMark struct {
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+	Title        string  `json:"title"`
+}
+
+// GetVideoChapters reads the chapter markers of a video. It returns an empty
+// slice when the file has no chapters.
+func (a *App) GetVideoChapters(videoPath string) ([]ChapterMark, error) {
+	if _, err := os.Stat(videoPath); err != nil {
+		return nil, fmt.Errorf("cannot access video file: %s. Error: %v", videoPath, err)
+	}
+
+	out, err := runFFprobe("-v", "error", "-show_chapters", "-of", "json", videoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe struct {
+		Chapters []struct {
+			TimeBase  string            `json:"time_base"`
+			Start     json.Number       `json:"start"`
+			End       json.Number       `json:"end"`
+			StartTime string            `json:"start_time"`
+			EndTime   string            `json:"end_time"`
+			Tags      map[string]string `json:"tags"`
+		} `json:"chapters"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %v", err)
+	}
+
+	chapters := make([]ChapterMark, 0, len(probe.Chapters))
+	for _, chapter := range probe.Chapters {
+		timeBase, err := parseFrameRate(chapter.TimeBase)
+		if err != nil {
+			timeBase = 0
+		}
+		chapters = append(chapters, ChapterMark{
+			StartSeconds: chapterTime(chapter.StartTime, chapter.Start, timeBase),
+			EndSeconds:   chapterTime(chapter.EndTime, chapter.End, timeBase),
+			Title:        chapter.Tags["title"],
+		})
+	}
+
+	return chapters, nil
+}
+
+// chapterTime prefers the start_time/end_time value in seconds and falls
+// back to the start/end value expressed in time_base units
+func chapterTime(seconds string, ticks json.Number, timeBase float64) float64 {
+	if v, err := strconv.ParseFloat(seconds, 64); err == nil {
+		return v
+	}
+	if v, err := ticks.Float64(); err == nil {
+		return v * timeBase
+	}
+	return 0
+}
+
+// backendVideoCodecs are the video codecs the backend's OpenCV build decodes reliably
+var backendVideoCodecs = []string{
+	"h264", "hevc", "mpeg4", "mpeg2video", "mpeg1video", "mjpeg",
+	"vp8", "vp9", "h263", "flv1", "msmpeg4v2", "msmpeg4v3", "wmv1", "wmv2",
+}
+
+// AudioStreamInfo describes one audio stream reported by ProbeVideo
+type AudioStreamInfo struct {
+	Index       int    `json:"index"`
+	Codec       string `json:"codec"`
+	SampleRate  int    `json:"sample_rate"`
+	Channels    int    `json:"channels"`
+	Language    string `json:"language,omitempty"`
+	BitRateKbps int    `json:"bit_rate_kbps,omitempty"`
+}
+
+// VideoMetadata is the file information shown before a video is processed
+type VideoMetadata struct {
+	Path            string  `json:"path"`
+	FormatName      string  `json:"format_name"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	BitRateKbps     int     `json:"bit_rate_kbps"`
+	SizeBytes       int64   `json:"size_bytes"`
+
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	FrameRate  float64 `json:"frame_rate"`
+	VideoCodec string  `json:"video_codec"`
+	Rotation   int     `json:"rotation"` // clockwise degrees: 0, 90, 180 or 270
+
+	AudioStreams []AudioStreamInfo `json:"audio_streams"`
+
+	// Supported is false when the backend is not expected to decode the video
+	Supported         bool   `json:"supported"`
+	UnsupportedReason string `json:"unsupported_reason,omitempty"`
+}
+
+// ProbeVideo reads a video's format, main video stream and audio streams with
+// a single ffprobe call, and reports whether the backend can decode it
+func (a *App) ProbeVideo(path string) (VideoMetadata, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return VideoMetadata{}, fmt.Errorf("cannot access video file: %s. Error: %v", path, err)
+	}
+
+	out, err := runFFprobe("-v", "error", "-show_format", "-show_streams", "-of", "json", path)
+	if err != nil {
+		return VideoMetadata{}, err
+	}
+
+	var probe struct {
+		Format struct {
+			FormatName string `json:"format_name"`
+			Duration   string `json:"duration"`
+			BitRate    string `json:"bit_rate"`
+		} `json:"format"`
+		Streams []ffprobeStream `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return VideoMetadata{}, fmt.Errorf("failed to parse ffprobe output: %v", err)
+	}
+
+	duration, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+	bitRate, _ := strconv.ParseFloat(probe.Format.BitRate, 64)
+	metadata := VideoMetadata{
+		Path:            path,
+		FormatName:      probe.Format.FormatName,
+		DurationSeconds: duration,
+		BitRateKbps:     int(bitRate / 1000),
+		SizeBytes:       info.Size(),
+		AudioStreams:    []AudioStreamInfo{},
+	}
+
+	foundVideo := false
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			// Cover art is stored as a single-frame video stream; skip it
+			if foundVideo || stream.CodecName == "png" || stream.CodecName == "mjpeg" && stream.AvgFrameRate == "0/0" {
+				continue
+			}
+			foundVideo = true
+			metadata.Width = stream.Width
+			metadata.Height = stream.Height
+			metadata.VideoCodec = stream.CodecName
+			metadata.FrameRate, _ = parseFrameRate(stream.AvgFrameRate)
+			metadata.Rotation, _ = streamRotation(stream)
+			if metadata.DurationSeconds == 0 {
+				metadata.DurationSeconds, _ = strconv.ParseFloat(stream.Duration, 64)
+			}
+		case "audio":
+			sampleRate, _ := strconv.Atoi(stream.SampleRate)
+			streamBitRate, _ := strconv.Atoi(stream.BitRate)
+			metadata.AudioStreams = append(metadata.AudioStreams, AudioStreamInfo{
+				Index:       stream.Index,
+				Codec:       stream.CodecName,
+				SampleRate:  sampleRate,
+				Channels:    stream.Channels,
+				Language:    stream.Tags["language"],
+				BitRateKbps: streamBitRate / 1000,
+			})
+		}
+	}
+
+	switch {
+	case !foundVideo:
+		metadata.UnsupportedReason = "The file has no video stream."
+	case !containsString(backendVideoCodecs, metadata.VideoCodec):
+		metadata.UnsupportedReason = fmt.Sprintf("The %s video codec is not supported. Convert the video to H.264 first.", metadata.VideoCodec)
+	default:
+		metadata.Supported = true
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,65 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group so that the
+// interpreter launched by uv can be stopped together with it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// lowPriorityNice is the niceness of backends started at low priority
+const lowPriorityNice = "10"
+
+// processAlive reports whether a process with the given ID exists. A process
+// owned by another user still counts as alive.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// setLowPriority makes the command run through nice and, where available,
+// ionice in the idle I/O class. Child processes inherit both.
+func setLowPriority(cmd *exec.Cmd) {
+	nice, err := exec.LookPath("nice")
+	if err != nil || cmd.Err != nil {
+		return
+	}
+	wrapped := []string{nice, "-n", lowPriorityNice}
+	if ionice, err := exec.LookPath("ionice"); err == nil {
+		wrapped = []string{ionice, "-c", "3", nice, "-n", lowPriorityNice}
+	}
+	cmd.Args = append(wrapped, append([]string{cmd.Path}, cmd.Args[1:]...)...)
+	cmd.Path = wrapped[0]
+}
+
+// isBackendCrash reports whether a backend process was killed by a signal
+// rather than exiting on its own, or a worker died mid-request
+func isBackendCrash(err error) bool {
+	if errors.Is(err, errWorkerExited) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled()
+}
+
+// terminateProcessTree sends SIGTERM to the command's process group
+func terminateProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
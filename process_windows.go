@@ -0,0 +1,67 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// setProcessGroup starts the command in a new process group so that the
+// interpreter launched by uv can be stopped together with it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// processAlive reports whether a process with the given ID is still running
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access is denied for processes of other users, which do exist
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(handle)
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	// STILL_ACTIVE
+	return code == 259
+}
+
+// setLowPriority starts the command in the below-normal priority class, which
+// its child processes inherit. Call it after setProcessGroup.
+func setLowPriority(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.BELOW_NORMAL_PRIORITY_CLASS
+}
+
+// isBackendCrash reports whether a backend process ended with an NTSTATUS
+// error code, such as an access violation, or a worker died mid-request
+func isBackendCrash(err error) bool {
+	if errors.Is(err, errWorkerExited) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	return uint32(exitErr.ExitCode())&0xC0000000 == 0xC0000000
+}
+
+// terminateProcessTree kills the command and all of its child processes
+func terminateProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// backendProgress is a progress record written by the backend as a single stdout line
+type backendProgress struct {
+	Type        string `json:"type"`
+	Stage       string `json:"stage"`
+	Frame       int    `json:"frame"`
+	TotalFrames int    `json:"total_frames"`
+}
+
+// ProcessingProgress is the payload of "processing:progress" events
+type ProcessingProgress struct {
+	JobID       string  `json:"job_id"`
+	InputPath   string  `json:"input_path"`
+	Stage       string  `json:"stage"`
+	Frame       int     `json:"frame"`
+	TotalFrames int     `json:"total_frames"`
+	Percent     float64 `json:"percent"`
+	ETASeconds  float64 `json:"eta_seconds"`
+}
+
+// progressTracker turns backend progress records into events, estimating the
+// remaining time of each stage from its elapsed time
+type progressTracker struct {
+	app        *App
+	jobID      string
+	inputPath  string
+	event      string // topic to emit, "processing:progress" when empty
+	stage      string
+	stageStart time.Time
+}
+
+// handle emits a progress event for the record
+func (t *progressTracker) handle(record backendProgress) {
+	now := time.Now()
+	if record.Stage != t.stage {
+		t.stage = record.Stage
+		t.stageStart = now
+	}
+
+	progress := ProcessingProgress{
+		JobID:       t.jobID,
+		InputPath:   t.inputPath,
+		Stage:       record.Stage,
+		Frame:       record.Frame,
+		TotalFrames: record.TotalFrames,
+	}
+	if record.TotalFrames > 0 {
+		progress.Percent = float64(record.Frame) / float64(record.TotalFrames) * 100
+	}
+	if record.Frame > 0 && record.TotalFrames > record.Frame {
+		perFrame := now.Sub(t.stageStart).Seconds() / float64(record.Frame)
+		progress.ETASeconds = perFrame * float64(record.TotalFrames-record.Frame)
+	}
+
+	event := t.event
+	if event == "" {
+		event = "processing:progress"
+	}
+	t.app.emit(event, progress)
+}
+
+// runBackendCommand runs the backend and streams its output. Progress records
+// on stdout are forwarded as "processing:progress" events and stderr lines as
+// "backend:log" events; the remaining stdout lines are returned as the result
+// output together with the captured stderr. The started process is attached
+// to the job so it can be cancelled.
+func (a *App) runBackendCommand(cmd *exec.Cmd, request ProcessVideoRequest, job *runningJob) ([]byte, []byte, error) {
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to attach to backend output: %v", err)
+	}
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to attach to backend output: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	a.attachJobProcess(job, func() error { return terminateProcessTree(cmd) })
+
+	var stderr lockedBuffer
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		a.streamBackendLog(stderrPipe, func() string { return request.JobID }, &stderr)
+	}()
+
+	tracker := &progressTracker{app: a, jobID: request.JobID, inputPath: request.InputPath}
+	result := a.readBackendOutput(stdoutPipe, tracker)
+	<-stderrDone
+
+	err = cmd.Wait()
+	return result, stderr.Bytes(), err
+}
+
+// readBackendOutput consumes line-delimited backend output until EOF,
+// handling progress records and collecting the remaining lines
+func (a *App) readBackendOutput(r io.Reader, tracker *progressTracker) []byte {
+	var result bytes.Buffer
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		var record backendProgress
+		if json.Unmarshal(line, &record) == nil && record.Type == "progress" {
+			tracker.handle(record)
+			continue
+		}
+
+		result.Write(line)
+		result.WriteByte('\n')
+	}
+	// Drain anything left so the process never blocks on a full pipe
+	_, _ = io.Copy(io.Discard, r)
+
+	return bytes.TrimSpace(result.Bytes())
+}
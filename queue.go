@@ -0,0 +1,593 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Queued job states
+const (
+	jobStatePending   = "pending"
+	jobStateRunning   = "running"
+	jobStateDone      = "done"
+	jobStateFailed    = "failed"
+	jobStateCancelled = "cancelled"
+	jobStateSkipped   = "skipped"
+)
+
+// QueuedJob is one entry of the batch queue
+type QueuedJob struct {
+	JobID       string                `json:"job_id"`
+	InputPath   string                `json:"input_path"`
+	OutputPath  string                `json:"output_path"`
+	Description string                `json:"description"`
+	State       string                `json:"state"`
+	EnqueuedAt  time.Time             `json:"enqueued_at"`
+	StartedAt   *time.Time            `json:"started_at,omitempty"`
+	FinishedAt  *time.Time            `json:"finished_at,omitempty"`
+	RunAt       *time.Time            `json:"run_at,omitempty"`    // earliest start of a scheduled job
+	OffHours    bool                  `json:"off_hours,omitempty"` // waits for the off-hours window
+	Metadata    map[string]string     `json:"metadata,omitempty"`
+	Response    *ProcessVideoResponse `json:"response,omitempty"`
+}
+
+// QueueState is a snapshot of the batch queue, sent with "queue:updated" events
+type QueueState struct {
+	Jobs        []QueuedJob `json:"jobs"`
+	Concurrency int         `json:"concurrency"`
+	Paused      bool        `json:"paused"`
+	Pending     int         `json:"pending"`
+	Running     int         `json:"running"`
+	Done        int         `json:"done"`
+	Failed      int         `json:"failed"`
+	Cancelled   int         `json:"cancelled"`
+	Skipped     int         `json:"skipped"`
+}
+
+// queueEntry is a queued job together with the request that runs it
+type queueEntry struct {
+	job     QueuedJob
+	request ProcessVideoRequest
+}
+
+// JobQueue runs queued requests in order, at most concurrency at a time
+type JobQueue struct {
+	mu          sync.Mutex
+	concurrency int
+	entries     []*queueEntry
+	running     int
+	paused      bool
+	offHours    offHoursWindow
+	wake        *time.Timer // re-runs dispatch when the next scheduled job is due
+
+	process  func(ProcessVideoRequest) ProcessVideoResponse
+	onUpdate func(QueueState)
+}
+
+// NewJobQueue creates a queue that runs requests with process and reports
+// every state change to onUpdate
+func NewJobQueue(concurrency int, process func(ProcessVideoRequest) ProcessVideoResponse, onUpdate func(QueueState)) *JobQueue {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &JobQueue{
+		concurrency: concurrency,
+		process:     process,
+		onUpdate:    onUpdate,
+	}
+}
+
+// Enqueue adds requests to the end of the queue and returns their job IDs. A
+// request keeps its own job ID unless it is empty or already used by another
+// job in the queue, in which case a new one is assigned.
+func (q *JobQueue) Enqueue(requests []ProcessVideoRequest) []string {
+	jobIDs := make([]string, 0, len(requests))
+
+	q.mu.Lock()
+	used := make(map[string]bool, len(q.entries)+len(requests))
+	for _, entry := range q.entries {
+		used[entry.job.JobID] = true
+	}
+	now := time.Now()
+	for _, request := range requests {
+		request.Description = jobDescription(request)
+		if request.JobID == "" || used[request.JobID] {
+			request.JobID = generateID()
+		}
+		used[request.JobID] = true
+		q.entries = append(q.entries, &queueEntry{
+			request: request,
+			job: QueuedJob{
+				JobID:       request.JobID,
+				InputPath:   request.InputPath,
+				OutputPath:  request.OutputPath,
+				Description: request.Description,
+				State:       jobStatePending,
+				EnqueuedAt:  now,
+				RunAt:       request.RunAt,
+				OffHours:    request.OffHoursOnly,
+				Metadata:    request.Metadata,
+			},
+		})
+		jobIDs = append(jobIDs, request.JobID)
+	}
+	q.mu.Unlock()
+
+	q.dispatch()
+	return jobIDs
+}
+
+// SetConcurrency changes how many jobs may run at once. Lowering it does not
+// stop jobs that are already running.
+func (q *JobQueue) SetConcurrency(n int) error {
+	if n < 1 {
+		return fmt.Errorf("concurrency must be at least 1, got %d", n)
+	}
+
+	q.mu.Lock()
+	q.concurrency = n
+	q.mu.Unlock()
+
+	q.dispatch()
+	return nil
+}
+
+// Pause stops new jobs from starting. Running jobs are left to finish.
+func (q *JobQueue) Pause() {
+	q.mu.Lock()
+	q.paused = true
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// Resume starts pending jobs again after Pause
+func (q *JobQueue) Resume() {
+	q.mu.Lock()
+	q.paused = false
+	q.mu.Unlock()
+
+	q.dispatch()
+}
+
+// PendingRequests returns the requests of the jobs that have not started yet, in queue order
+func (q *JobQueue) PendingRequests() []ProcessVideoRequest {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	requests := []ProcessVideoRequest{}
+	for _, entry := range q.entries {
+		if entry.job.State == jobStatePending {
+			requests = append(requests, entry.request)
+		}
+	}
+	return requests
+}
+
+// State returns a snapshot of the queue
+func (q *JobQueue) State() QueueState {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stateLocked()
+}
+
+// stateLocked builds the queue snapshot; q.mu must be held
+func (q *JobQueue) stateLocked() QueueState {
+	state := QueueState{
+		Jobs:        make([]QueuedJob, 0, len(q.entries)),
+		Concurrency: q.concurrency,
+		Paused:      q.paused,
+	}
+	for _, entry := range q.entries {
+		state.Jobs = append(state.Jobs, entry.job)
+		switch entry.job.State {
+		case jobStatePending:
+			state.Pending++
+		case jobStateRunning:
+			state.Running++
+		case jobStateDone:
+			state.Done++
+		case jobStateFailed:
+			state.Failed++
+		case jobStateCancelled:
+			state.Cancelled++
+		case jobStateSkipped:
+			state.Skipped++
+		}
+	}
+	return state
+}
+
+// CancelPending removes a job from the queue before it starts. It returns
+// false if the job is unknown or no longer pending.
+func (q *JobQueue) CancelPending(jobID string) bool {
+	q.mu.Lock()
+	cancelled := false
+	for _, entry := range q.entries {
+		if entry.job.JobID == jobID && entry.job.State == jobStatePending {
+			now := time.Now()
+			entry.job.State = jobStateCancelled
+			entry.job.FinishedAt = &now
+			cancelled = true
+			break
+		}
+	}
+	q.mu.Unlock()
+
+	if cancelled {
+		q.notify()
+	}
+	return cancelled
+}
+
+// CancelAllPending cancels every job that has not started and returns how many there were
+func (q *JobQueue) CancelAllPending() int {
+	q.mu.Lock()
+	now := time.Now()
+	cancelled := 0
+	for _, entry := range q.entries {
+		if entry.job.State == jobStatePending {
+			entry.job.State = jobStateCancelled
+			entry.job.FinishedAt = &now
+			cancelled++
+		}
+	}
+	q.mu.Unlock()
+
+	if cancelled > 0 {
+		q.notify()
+	}
+	return cancelled
+}
+
+// PendingWithMetadata returns the IDs of the pending jobs whose metadata maps
+// key to value, in queue order
+func (q *JobQueue) PendingWithMetadata(key, value string) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobIDs := []string{}
+	for _, entry := range q.entries {
+		if entry.job.State == jobStatePending && hasMetadata(entry.request, key, value) {
+			jobIDs = append(jobIDs, entry.job.JobID)
+		}
+	}
+	return jobIDs
+}
+
+// PrioritizeMetadata moves the pending jobs whose metadata maps key to value
+// to the front of the queue, keeping their order, and returns how many moved
+func (q *JobQueue) PrioritizeMetadata(key, value string) int {
+	q.mu.Lock()
+	matched := make([]*queueEntry, 0, len(q.entries))
+	others := make([]*queueEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		if entry.job.State == jobStatePending && hasMetadata(entry.request, key, value) {
+			matched = append(matched, entry)
+		} else {
+			others = append(others, entry)
+		}
+	}
+	q.entries = append(matched, others...)
+	q.mu.Unlock()
+
+	q.dispatch()
+	return len(matched)
+}
+
+// hasMetadata reports whether the request's metadata maps key to value
+func hasMetadata(request ProcessVideoRequest, key, value string) bool {
+	got, ok := request.Metadata[key]
+	return ok && got == value
+}
+
+// ClearFinished drops done, failed, cancelled and skipped jobs from the queue
+func (q *JobQueue) ClearFinished() {
+	q.mu.Lock()
+	kept := q.entries[:0]
+	for _, entry := range q.entries {
+		if entry.job.State == jobStatePending || entry.job.State == jobStateRunning {
+			kept = append(kept, entry)
+		}
+	}
+	q.entries = kept
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// dispatch starts pending jobs that are due while there is free capacity and
+// the queue is not paused. Jobs waiting for their schedule are passed over,
+// and a wake-up is set for the earliest of them.
+func (q *JobQueue) dispatch() {
+	q.mu.Lock()
+	now := time.Now()
+	var next time.Time
+	for _, entry := range q.entries {
+		if q.paused || q.running >= q.concurrency {
+			break
+		}
+		if entry.job.State != jobStatePending {
+			continue
+		}
+		if due, at := q.dueLocked(entry, now); !due {
+			if next.IsZero() || at.Before(next) {
+				next = at
+			}
+			continue
+		}
+		entry.job.State = jobStateRunning
+		entry.job.StartedAt = &now
+		q.running++
+		go q.run(entry)
+	}
+	q.scheduleWakeLocked(next)
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// run processes one entry and starts the next pending job when it finishes
+func (q *JobQueue) run(entry *queueEntry) {
+	response := q.process(entry.request)
+
+	q.mu.Lock()
+	now := time.Now()
+	entry.job.FinishedAt = &now
+	entry.job.Response = &response
+	switch response.Status {
+	case "success":
+		entry.job.State = jobStateDone
+	case "cancelled":
+		entry.job.State = jobStateCancelled
+	case "skipped":
+		entry.job.State = jobStateSkipped
+	default:
+		entry.job.State = jobStateFailed
+	}
+	q.running--
+	q.mu.Unlock()
+
+	q.dispatch()
+}
+
+// notify reports the current state to onUpdate
+func (q *JobQueue) notify() {
+	if q.onUpdate != nil {
+		q.onUpdate(q.State())
+	}
+}
+
+// jobQueue returns the app's batch queue, creating it on first use
+func (a *App) jobQueue() *JobQueue {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.queue == nil {
+		var queue *JobQueue
+		stats := newQueueStatsThrottle(queueStatsInterval, func(depth QueueDepth) {
+			a.emit("queue:stats", depth)
+		})
+		queue = NewJobQueue(a.config.QueueConcurrency, a.ProcessVideo, func(state QueueState) {
+			a.emit("queue:updated", state)
+			stats.update(queueDepth(state))
+			if err := saveQueueFile(queue); err != nil {
+				a.logger.Warn("failed to save queue state", map[string]interface{}{"error": err.Error()})
+			}
+		})
+		// Validated when the settings were applied
+		queue.offHours, _ = parseOffHours(a.settings.OffHours)
+		a.queue = queue
+	}
+	return a.queue
+}
+
+// PauseQueue stops the batch queue from starting new jobs. Jobs that are
+// already running finish normally. The paused state survives a restart.
+func (a *App) PauseQueue() {
+	a.jobQueue().Pause()
+	a.logger.Info("queue paused", nil)
+}
+
+// ResumeQueue lets the batch queue start pending jobs again
+func (a *App) ResumeQueue() {
+	a.jobQueue().Resume()
+	a.logger.Info("queue resumed", nil)
+}
+
+// queueFile is what is persisted of the batch queue between sessions
+type queueFile struct {
+	Paused  bool                  `json:"paused"`
+	Pending []ProcessVideoRequest `json:"pending"`
+}
+
+// queueFilePath returns the path of the persisted queue state
+func queueFilePath() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "queue.json"), nil
+}
+
+// queueFileMu serialises writes of the persisted queue state
+var queueFileMu sync.Mutex
+
+// saveQueueFile writes the queue's paused flag and pending requests. The file
+// is replaced atomically so a crash mid-write keeps the previous state.
+func saveQueueFile(queue *JobQueue) error {
+	queueFileMu.Lock()
+	defer queueFileMu.Unlock()
+
+	path, err := queueFilePath()
+	if err != nil {
+		return err
+	}
+	// Read the queue under the lock so a slower writer cannot persist an older state
+	saved := queueFile{Paused: queue.State().Paused, Pending: queue.PendingRequests()}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode queue state: %v", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write queue state: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write queue state: %v", err)
+	}
+	return nil
+}
+
+// PersistQueue saves the queue's paused flag and pending jobs now. The queue
+// is also saved on every change, such as a job being added or finishing.
+func (a *App) PersistQueue() error {
+	return saveQueueFile(a.jobQueue())
+}
+
+// minQueuePersistenceInterval is the shortest interval SetQueuePersistenceInterval accepts
+const minQueuePersistenceInterval = 5 * time.Second
+
+// SetQueuePersistenceInterval saves the queue every d in addition to the saves
+// on every change, replacing any interval set before. It runs until
+// StopQueuePersistenceTicker or shutdown.
+func (a *App) SetQueuePersistenceInterval(d time.Duration) error {
+	if d < minQueuePersistenceInterval {
+		return fmt.Errorf("queue persistence interval must be at least %s, got %s", minQueuePersistenceInterval, d)
+	}
+
+	stop := make(chan struct{})
+	a.mu.Lock()
+	previous := a.persistStop
+	a.persistStop = stop
+	a.mu.Unlock()
+	if previous != nil {
+		close(previous)
+	}
+
+	go func() {
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := a.PersistQueue(); err != nil {
+					a.logger.Warn("failed to save queue state", map[string]interface{}{"error": err.Error()})
+				}
+			}
+		}
+	}()
+	a.logger.Info("queue persistence interval set", map[string]interface{}{"interval": d.String()})
+	return nil
+}
+
+// StopQueuePersistenceTicker stops the periodic saves started by SetQueuePersistenceInterval
+func (a *App) StopQueuePersistenceTicker() error {
+	a.mu.Lock()
+	stop := a.persistStop
+	a.persistStop = nil
+	a.mu.Unlock()
+
+	if stop == nil {
+		return fmt.Errorf("queue persistence ticker is not running")
+	}
+	close(stop)
+	return nil
+}
+
+// restoreQueue reloads the queue persisted by the previous session. Pending
+// jobs are enqueued again; if the queue was paused it stays paused, so they
+// wait for ResumeQueue.
+func (a *App) restoreQueue() {
+	path, err := queueFilePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var saved queueFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		a.logger.Warn("ignoring unreadable queue state", map[string]interface{}{"path": path, "error": err.Error()})
+		return
+	}
+	if !saved.Paused && len(saved.Pending) == 0 {
+		return
+	}
+
+	queue := a.jobQueue()
+	if saved.Paused {
+		queue.Pause()
+	}
+	if len(saved.Pending) > 0 {
+		queue.Enqueue(saved.Pending)
+	}
+	a.logger.Info("restored queue", map[string]interface{}{
+		"paused":  saved.Paused,
+		"pending": len(saved.Pending),
+	})
+}
+
+// EnqueueVideos adds requests to the batch queue and returns their job IDs.
+// Progress is reported through "queue:updated" events, and the number of
+// waiting and running jobs through "queue:stats" events. Job IDs that are taken
+// by another job are replaced.
+func (a *App) EnqueueVideos(requests []ProcessVideoRequest) []string {
+	// Jobs started with ProcessVideo run outside the queue
+	a.mu.RLock()
+	for i := range requests {
+		if _, running := a.jobs[requests[i].JobID]; running {
+			requests[i].JobID = ""
+		}
+	}
+	a.mu.RUnlock()
+	return a.jobQueue().Enqueue(requests)
+}
+
+// GetQueueState returns the jobs in the batch queue and their states
+func (a *App) GetQueueState() QueueState {
+	return a.jobQueue().State()
+}
+
+// SetQueueConcurrency sets how many queued jobs run at the same time and saves it in the settings
+func (a *App) SetQueueConcurrency(n int) error {
+	settings := a.GetSettings()
+	settings.QueueConcurrency = n
+	_, err := a.UpdateSettings(settings)
+	return err
+}
+
+// FilterQueueByMetadata returns the IDs of the pending queued jobs whose
+// metadata maps key to value, without changing the queue
+func (a *App) FilterQueueByMetadata(key string, value string) []string {
+	return a.jobQueue().PendingWithMetadata(key, value)
+}
+
+// RunJobsWithTag moves the pending queued jobs whose metadata maps tag to
+// tagValue ahead of all other pending jobs, so they start next, and returns
+// how many matched
+func (a *App) RunJobsWithTag(tag string, tagValue string) (int, error) {
+	if tag == "" {
+		return 0, fmt.Errorf("a tag is required")
+	}
+	matched := a.jobQueue().PrioritizeMetadata(tag, tagValue)
+	a.logger.Info("queued jobs prioritized by tag", map[string]interface{}{
+		"tag":     tag,
+		"value":   tagValue,
+		"matched": matched,
+	})
+	return matched, nil
+}
+
+// ClearFinishedJobs removes completed, failed and cancelled jobs from the batch queue
+func (a *App) ClearFinishedJobs() {
+	a.jobQueue().ClearFinished()
+}
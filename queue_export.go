@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// queueExport is the file format of ExportJobQueue and ImportJobQueue
+type queueExport struct {
+	Jobs []ProcessVideoRequest `json:"jobs"`
+}
+
+// ExportJobQueue writes the requests of the queued jobs that have not started
+// to outputPath, so the batch can be imported on another machine
+func (a *App) ExportJobQueue(outputPath string) error {
+	if outputPath == "" {
+		return fmt.Errorf("an output path is required")
+	}
+	absPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("invalid output path %s: %v", outputPath, err)
+	}
+
+	exported := queueExport{Jobs: a.jobQueue().PendingRequests()}
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job queue: %v", err)
+	}
+	if err := writeFileAtomic(absPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write job queue: %v", err)
+	}
+	a.logger.Info("job queue exported", map[string]interface{}{"path": absPath, "jobs": len(exported.Jobs)})
+	return nil
+}
+
+// ImportJobQueue enqueues the jobs of a file written by ExportJobQueue and
+// returns how many were added. Without merge, the pending jobs already in the
+// queue are cancelled first. Every request is checked before anything
+// changes, so a file with an invalid job imports nothing.
+func (a *App) ImportJobQueue(inputPath string, merge bool) (int, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read job queue %s: %v", inputPath, err)
+	}
+	var imported queueExport
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return 0, fmt.Errorf("invalid job queue file %s: %v", inputPath, err)
+	}
+	for i, request := range imported.Jobs {
+		if invalid := precheckRequest(request); invalid != nil {
+			return 0, fmt.Errorf("job %d (%s): %s: %s", i+1, request.InputPath, invalid.ErrorType, invalid.Message)
+		}
+	}
+
+	queue := a.jobQueue()
+	cancelled := 0
+	if !merge {
+		cancelled = queue.CancelAllPending()
+	}
+	if len(imported.Jobs) > 0 {
+		a.EnqueueVideos(imported.Jobs)
+	}
+	a.logger.Info("job queue imported", map[string]interface{}{
+		"path":      inputPath,
+		"jobs":      len(imported.Jobs),
+		"merge":     merge,
+		"cancelled": cancelled,
+	})
+	return len(imported.Jobs), nil
+}
+
+// precheckRequest checks the parts of a request that do not depend on the
+// machine's backend setup, returning an error response for the first problem
+// or nil. processVideo repeats these checks when the job runs.
+func precheckRequest(request ProcessVideoRequest) *ProcessVideoResponse {
+	invalid := func(errorType, format string, args ...interface{}) *ProcessVideoResponse {
+		return &ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: errorType,
+			Message:   fmt.Sprintf(format, args...),
+		}
+	}
+
+	if request.InputPath == "" {
+		return invalid("ValidationError", "Input video path is required.")
+	}
+	if request.OutputPath == "" {
+		return invalid("ValidationError", "Output path is required.")
+	}
+	if response := checkDescription(request); response != nil {
+		return response
+	}
+	config := request.Config
+	if request.AnalysisConfig != nil {
+		if err := request.AnalysisConfig.Validate(); err != nil {
+			return invalid("ConfigurationError", "Invalid analysis parameters: %v.", err)
+		}
+	} else if config == "" {
+		return invalid("ValidationError", "Analysis configuration is required.")
+	} else if analysisConfig, err := ParseAnalysisConfig(config); err != nil {
+		return invalid("ConfigurationError", "Invalid configuration format: %v.", err)
+	} else if err := analysisConfig.Validate(); err != nil {
+		return invalid("ConfigurationError", "Invalid analysis parameters: %v.", err)
+	}
+	if err := validateBackendEnv(request.Env); err != nil {
+		return invalid("ValidationError", "Invalid environment configuration: %v.", err)
+	}
+	if _, err := os.Stat(request.InputPath); os.IsNotExist(err) {
+		return invalid("FileNotFoundError", "Input video file not found: %s.", request.InputPath)
+	} else if err != nil {
+		return invalid("FileAccessError", "Cannot access input video file: %s. Error: %v", request.InputPath, err)
+	}
+	return checkTrimRange(request)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// queueStatsInterval is the shortest time between two "queue:stats" events
+const queueStatsInterval = 200 * time.Millisecond
+
+// QueueDepth is the payload of "queue:stats" events
+type QueueDepth struct {
+	Pending int `json:"pending"`
+	Running int `json:"running"`
+	Total   int `json:"total"` // pending plus running
+}
+
+// queueDepth returns the depth of a queue snapshot
+func queueDepth(state QueueState) QueueDepth {
+	return QueueDepth{Pending: state.Pending, Running: state.Running, Total: state.Pending + state.Running}
+}
+
+// queueStatsThrottle passes queue depths on to emit when they change, at
+// most once per interval. A change inside the interval is sent when it ends,
+// so the last depth is always delivered.
+type queueStatsThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	emit     func(QueueDepth)
+	latest   QueueDepth
+	sent     QueueDepth
+	sentAt   time.Time
+	timer    *time.Timer // pending trailing emission
+}
+
+func newQueueStatsThrottle(interval time.Duration, emit func(QueueDepth)) *queueStatsThrottle {
+	return &queueStatsThrottle{interval: interval, emit: emit}
+}
+
+// update records the current depth
+func (t *queueStatsThrottle) update(depth QueueDepth) {
+	t.mu.Lock()
+	t.latest = depth
+	if t.timer != nil || depth == t.sent {
+		t.mu.Unlock()
+		return
+	}
+	if wait := t.interval - time.Since(t.sentAt); wait > 0 {
+		t.timer = time.AfterFunc(wait, t.flush)
+		t.mu.Unlock()
+		return
+	}
+	t.mu.Unlock()
+	t.flush()
+}
+
+// flush emits the latest depth if it differs from the last one sent
+func (t *queueStatsThrottle) flush() {
+	t.mu.Lock()
+	t.timer = nil
+	depth := t.latest
+	if depth == t.sent {
+		t.mu.Unlock()
+		return
+	}
+	t.sent = depth
+	t.sentAt = time.Now()
+	t.mu.Unlock()
+
+	t.emit(depth)
+}
+
+// SubscribeToQueueStats calls callback with every "queue:stats" event until
+// the returned function is called. callback runs on the emitting goroutine
+// and must not block. Function arguments cannot come from the frontend, which
+// listens to the event instead, so this is for Go callers.
+func (a *App) SubscribeToQueueStats(callback func(QueueDepth)) func() {
+	return a.subscribeEvents(func(topic string, data interface{}) {
+		if depth, ok := data.(QueueDepth); ok && topic == "queue:stats" {
+			callback(depth)
+		}
+	})
+}
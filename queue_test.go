@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// blockingProcessor is a JobQueue process function that holds every job until
+// release is closed and records how many ran at the same time
+type blockingProcessor struct {
+	mu        sync.Mutex
+	active    int
+	maxActive int
+	started   []string
+	release   chan struct{}
+	status    string
+}
+
+func newBlockingProcessor(status string) *blockingProcessor {
+	return &blockingProcessor{release: make(chan struct{}), status: status}
+}
+
+func (p *blockingProcessor) process(request ProcessVideoRequest) ProcessVideoResponse {
+	p.mu.Lock()
+	p.active++
+	p.maxActive = max(p.maxActive, p.active)
+	p.started = append(p.started, request.JobID)
+	p.mu.Unlock()
+
+	<-p.release
+
+	p.mu.Lock()
+	p.active--
+	p.mu.Unlock()
+	return ProcessVideoResponse{Status: p.status, JobID: request.JobID}
+}
+
+func (p *blockingProcessor) maxConcurrent() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maxActive
+}
+
+func (p *blockingProcessor) startedJobs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.started...)
+}
+
+// requestsWithIDs returns one request per job ID
+func requestsWithIDs(ids ...string) []ProcessVideoRequest {
+	requests := make([]ProcessVideoRequest, len(ids))
+	for i, id := range ids {
+		requests[i] = ProcessVideoRequest{JobID: id, InputPath: id + ".mp4", OutputPath: id + "_out.mp4"}
+	}
+	return requests
+}
+
+func TestJobQueueConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		jobs        int
+		wantRunning int
+	}{
+		{name: "one at a time", concurrency: 1, jobs: 4, wantRunning: 1},
+		{name: "two at a time", concurrency: 2, jobs: 5, wantRunning: 2},
+		{name: "more slots than jobs", concurrency: 8, jobs: 3, wantRunning: 3},
+		{name: "zero is raised to one", concurrency: 0, jobs: 2, wantRunning: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := newBlockingProcessor("success")
+			queue := NewJobQueue(tt.concurrency, processor.process, nil)
+			requests := make([]ProcessVideoRequest, tt.jobs)
+			queue.Enqueue(requests)
+
+			waitFor(t, "jobs to start", func() bool { return len(processor.startedJobs()) == tt.wantRunning })
+			// Give the queue a chance to start more jobs than it should
+			time.Sleep(20 * time.Millisecond)
+			state := queue.State()
+			if state.Running != tt.wantRunning || state.Pending != tt.jobs-tt.wantRunning {
+				t.Errorf("running = %d, pending = %d, want %d, %d", state.Running, state.Pending, tt.wantRunning, tt.jobs-tt.wantRunning)
+			}
+
+			close(processor.release)
+			waitFor(t, "jobs to finish", func() bool { return queue.State().Done == tt.jobs })
+			if got := processor.maxConcurrent(); got != tt.wantRunning {
+				t.Errorf("max concurrent jobs = %d, want %d", got, tt.wantRunning)
+			}
+		})
+	}
+}
+
+func TestJobQueueDispatchOrder(t *testing.T) {
+	processor := newBlockingProcessor("success")
+	close(processor.release)
+	queue := NewJobQueue(1, processor.process, nil)
+	queue.Pause()
+	queue.Enqueue(requestsWithIDs("a", "b", "c"))
+	queue.Resume()
+
+	waitFor(t, "jobs to finish", func() bool { return queue.State().Done == 3 })
+	if got, want := processor.startedJobs(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("start order = %q, want %q", got, want)
+	}
+}
+
+func TestJobQueueFinalStates(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{status: "success", want: jobStateDone},
+		{status: "cancelled", want: jobStateCancelled},
+		{status: "skipped", want: jobStateSkipped},
+		{status: "error", want: jobStateFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			processor := newBlockingProcessor(tt.status)
+			close(processor.release)
+			queue := NewJobQueue(1, processor.process, nil)
+			queue.Enqueue(requestsWithIDs("job"))
+
+			waitFor(t, "the job to finish", func() bool { return queue.State().Running+queue.State().Pending == 0 })
+			job := queue.State().Jobs[0]
+			if job.State != tt.want {
+				t.Errorf("state = %q, want %q", job.State, tt.want)
+			}
+			if job.Response == nil || job.Response.Status != tt.status || job.FinishedAt == nil {
+				t.Errorf("job = %+v, want the response and finish time recorded", job)
+			}
+		})
+	}
+}
+
+func TestJobQueuePauseResume(t *testing.T) {
+	processor := newBlockingProcessor("success")
+	queue := NewJobQueue(1, processor.process, nil)
+	queue.Enqueue(requestsWithIDs("a", "b"))
+	waitFor(t, "the first job to start", func() bool { return len(processor.startedJobs()) == 1 })
+
+	// Pausing leaves the running job alone but holds the next one back
+	queue.Pause()
+	processor.release <- struct{}{}
+	waitFor(t, "the first job to finish", func() bool { return queue.State().Done == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if state := queue.State(); !state.Paused || state.Pending != 1 || state.Running != 0 {
+		t.Fatalf("paused state = %+v, want one pending job and none running", state)
+	}
+
+	queue.Resume()
+	waitFor(t, "the second job to start", func() bool { return len(processor.startedJobs()) == 2 })
+	close(processor.release)
+	waitFor(t, "the second job to finish", func() bool { return queue.State().Done == 2 })
+	if queue.State().Paused {
+		t.Error("queue still paused after Resume")
+	}
+}
+
+func TestJobQueueEnqueueJobIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		requests []string
+		// keep[i] says whether requests[i] should keep its own ID
+		keep []bool
+	}{
+		{name: "unique IDs are kept", requests: []string{"a", "b"}, keep: []bool{true, true}},
+		{name: "empty IDs are generated", requests: []string{"", ""}, keep: []bool{false, false}},
+		{name: "ID of a queued job is replaced", existing: []string{"a"}, requests: []string{"a", "b"}, keep: []bool{false, true}},
+		{name: "duplicate within a batch is replaced", requests: []string{"a", "a"}, keep: []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewJobQueue(1, newBlockingProcessor("success").process, nil)
+			queue.Pause()
+			queue.Enqueue(requestsWithIDs(tt.existing...))
+			ids := queue.Enqueue(requestsWithIDs(tt.requests...))
+
+			for i, id := range ids {
+				if kept := id == tt.requests[i]; kept != tt.keep[i] || id == "" {
+					t.Errorf("job %d got ID %q from %q, want kept = %v", i, id, tt.requests[i], tt.keep[i])
+				}
+			}
+			seen := map[string]bool{}
+			for _, job := range queue.State().Jobs {
+				if seen[job.JobID] {
+					t.Errorf("job ID %q used twice", job.JobID)
+				}
+				seen[job.JobID] = true
+			}
+			pending := queue.PendingRequests()
+			for i, request := range pending[len(tt.existing):] {
+				if request.JobID != ids[i] {
+					t.Errorf("request %d runs as %q, want %q", i, request.JobID, ids[i])
+				}
+			}
+		})
+	}
+}
+
+// newTestApp returns an app whose data and cache directories are private to the test
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	app := NewAppWithLogger(NewJSONLogger(io.Discard))
+	app.config.UsePersistentBackend = false
+	return app
+}
+
+func TestEnqueueVideosReplacesRunningJobID(t *testing.T) {
+	app := newTestApp(t)
+	app.jobs = map[string]*runningJob{"busy": {}}
+	app.PauseQueue()
+
+	ids := app.EnqueueVideos(requestsWithIDs("busy", "free"))
+	if ids[0] == "busy" || ids[0] == "" {
+		t.Errorf("job ID = %q, want a new ID instead of the running job's", ids[0])
+	}
+	if ids[1] != "free" {
+		t.Errorf("job ID = %q, want %q", ids[1], "free")
+	}
+}
+
+func TestRestoreQueue(t *testing.T) {
+	tests := []struct {
+		name        string
+		saved       queueFile
+		wantPaused  bool
+		wantPending []string
+	}{
+		{
+			name:        "paused queue stays paused",
+			saved:       queueFile{Paused: true, Pending: requestsWithIDs("a", "b")},
+			wantPaused:  true,
+			wantPending: []string{"a", "b"},
+		},
+		{
+			name:        "paused empty queue",
+			saved:       queueFile{Paused: true},
+			wantPaused:  true,
+			wantPending: []string{},
+		},
+		{
+			name:        "nothing saved",
+			saved:       queueFile{},
+			wantPending: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			path, err := queueFilePath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(tt.saved)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			app.restoreQueue()
+			state := app.GetQueueState()
+			if state.Paused != tt.wantPaused {
+				t.Errorf("paused = %v, want %v", state.Paused, tt.wantPaused)
+			}
+			pending := []string{}
+			for _, request := range app.jobQueue().PendingRequests() {
+				pending = append(pending, request.JobID)
+			}
+			if !reflect.DeepEqual(pending, tt.wantPending) {
+				t.Errorf("pending = %q, want %q", pending, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestQueueFileRoundTrip(t *testing.T) {
+	app := newTestApp(t)
+	app.PauseQueue()
+	app.EnqueueVideos(requestsWithIDs("a", "b"))
+
+	// The queue is saved on every change; a new session picks it up
+	restored := NewAppWithLogger(NewJSONLogger(io.Discard))
+	restored.restoreQueue()
+	pending := []string{}
+	for _, request := range restored.jobQueue().PendingRequests() {
+		pending = append(pending, request.JobID)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(pending, want) || !restored.GetQueueState().Paused {
+		t.Errorf("restored pending = %q, paused = %v, want %q, true", pending, restored.GetQueueState().Paused, want)
+	}
+
+	path, _ := queueFilePath()
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary queue file left behind: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// legacyLastResultFileName is the single result file written by earlier
+// versions after every run; it cannot tell a crash from a finished job
+const legacyLastResultFileName = "last_result.json"
+
+// resultRecoveryDir returns the directory holding raw backend output of jobs
+// whose result is still being handled, one "<job ID>.json" file per job.
+// This replaces the single last_result.json, deleted whenever a new job
+// started, that result recovery was first specified with: with queued jobs
+// running side by side, one job starting would delete the result another had
+// just received. announceRecoveredResult removes a leftover last_result.json.
+func resultRecoveryDir() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "pending_results")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create result directory %s: %v", dir, err)
+	}
+	return dir, nil
+}
+
+// resultRecoveryPath returns the location of a job's persisted backend output
+func resultRecoveryPath(jobID string) (string, error) {
+	dir, err := resultRecoveryDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, jobID+".json"), nil
+}
+
+// persistLastResult saves the raw backend output of a job before it is
+// parsed, so a crash while handling it does not lose the result
+func (a *App) persistLastResult(jobID string, stdout []byte) {
+	path, err := resultRecoveryPath(jobID)
+	if err == nil {
+		err = os.WriteFile(path, stdout, 0644)
+	}
+	if err != nil {
+		a.logger.Warn("failed to persist backend result", map[string]interface{}{"job_id": jobID, "error": err.Error()})
+	}
+}
+
+// clearLastResult removes the persisted backend output of a job once it has
+// been handled
+func (a *App) clearLastResult(jobID string) {
+	path, err := resultRecoveryPath(jobID)
+	if err != nil {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		a.logger.Warn("failed to remove persisted backend result", map[string]interface{}{"job_id": jobID, "error": err.Error()})
+	}
+}
+
+// RecoveredResult is backend output a previous session received but did not
+// finish handling
+type RecoveredResult struct {
+	JobID    string    `json:"job_id"`
+	Result   string    `json:"result"` // raw backend output
+	Received time.Time `json:"received"`
+}
+
+// recoveredResults reads the persisted results of jobs that are not being
+// handled, newest first. Results of jobs still running here or in another
+// live process are skipped.
+func (a *App) recoveredResults() ([]RecoveredResult, error) {
+	dir, err := resultRecoveryDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result directory %s: %v", dir, err)
+	}
+
+	a.mu.RLock()
+	running := make(map[string]bool, len(a.jobs))
+	for jobID := range a.jobs {
+		running[jobID] = true
+	}
+	a.mu.RUnlock()
+
+	results := []RecoveredResult{}
+	for _, entry := range entries {
+		jobID, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || running[jobID] || jobRunningElsewhere(jobID) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		results = append(results, RecoveredResult{JobID: jobID, Result: string(data), Received: info.ModTime()})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Received.After(results[j].Received) })
+	return results, nil
+}
+
+// announceRecoveredResult emits "result:recovered" for every backend result a
+// previous session left behind, after deleting the legacy last_result.json,
+// which is no longer read
+func (a *App) announceRecoveredResult() {
+	if dir, err := appDataDir(); err == nil {
+		os.Remove(filepath.Join(dir, legacyLastResultFileName))
+	}
+
+	results, err := a.recoveredResults()
+	if err != nil {
+		a.logger.Warn("failed to check for recovered results", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	for _, result := range results {
+		a.emit("result:recovered", result)
+	}
+}
+
+// GetLastRecoveredResult returns the newest backend result persisted by a
+// previous session, or nil if there is none. The persisted file is removed
+// once it parses.
+func (a *App) GetLastRecoveredResult() (*ProcessVideoResponse, error) {
+	results, err := a.recoveredResults()
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	var response ProcessVideoResponse
+	if err := json.Unmarshal([]byte(results[0].Result), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse recovered result of job %s: %v", results[0].JobID, err)
+	}
+
+	a.clearLastResult(results[0].JobID)
+	return &response, nil
+}
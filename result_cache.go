@@ -0,0 +1,320 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// inputHashSampleSize is how much of the start and end of an input file is
+// hashed. Together with the size and modification time this identifies a file
+// without reading all of a multi-gigabyte video.
+const inputHashSampleSize = 1024 * 1024
+
+// cachedResult is a stored successful run, kept as <key>.json in the cache directory
+type cachedResult struct {
+	Key        string               `json:"key"`
+	InputPath  string               `json:"input_path"`
+	OutputPath string               `json:"output_path"`
+	OutputSize int64                `json:"output_size"`
+	OutputTime time.Time            `json:"output_time"` // modification time when stored
+	Response   ProcessVideoResponse `json:"response"`
+	StoredAt   time.Time            `json:"stored_at"`
+}
+
+// CacheStats describes the result cache
+type CacheStats struct {
+	Entries     int   `json:"entries"`
+	OutputBytes int64 `json:"output_bytes"` // size of the outputs the entries point to
+	Hits        int   `json:"hits"`         // since the application started
+	Misses      int   `json:"misses"`       // since the application started
+}
+
+// resultCacheCounters counts lookups since the application started
+var resultCacheCounters struct {
+	sync.Mutex
+	hits, misses int
+}
+
+// resultCacheDir returns the directory holding the result cache index
+func resultCacheDir() (string, error) {
+	return appCacheDir("results")
+}
+
+// hashInputFile returns a digest of the file's size, modification time and the
+// first and last inputHashSampleSize bytes
+func hashInputFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\x00%d\x00", info.Size(), info.ModTime().UnixNano())
+	if _, err := io.CopyN(hash, file, inputHashSampleSize); err != nil && err != io.EOF {
+		return "", err
+	}
+	if info.Size() > 2*inputHashSampleSize {
+		if _, err := file.Seek(-inputHashSampleSize, io.SeekEnd); err != nil {
+			return "", err
+		}
+		if _, err := io.CopyN(hash, file, inputHashSampleSize); err != nil && err != io.EOF {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// resultCacheKey combines the input hash, the normalized analysis config and
+// a hash of the backend scripts that run the request, so a changed or
+// different backend does not reuse old results
+func (a *App) resultCacheKey(request ProcessVideoRequest) (string, error) {
+	config := DefaultAnalysisConfig()
+	if request.AnalysisConfig != nil {
+		config = *request.AnalysisConfig
+	} else {
+		parsed, err := ParseAnalysisConfig(request.Config)
+		if err != nil {
+			return "", err
+		}
+		config = parsed
+	}
+	normalized, err := config.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	inputHash, err := hashInputFile(request.InputPath)
+	if err != nil {
+		return "", err
+	}
+	// A per-request working directory carries its own copy of the backend
+	backendDir := a.backendDir()
+	if request.WorkingDir != "" {
+		backendDir = filepath.Dir(backendScriptPath(request.WorkingDir))
+	}
+	if backendDir == "" {
+		return "", fmt.Errorf("no backend available")
+	}
+	backendHash, err := backendDirHash(backendDir)
+	if err != nil {
+		return "", err
+	}
+
+	material := inputHash + "\x00" + normalized + "\x00" + backendHash
+	// Only trimmed runs add the segment, so existing keys stay valid
+	if request.StartTime != 0 || request.EndTime != 0 {
+		material += fmt.Sprintf("\x00%g-%g", request.StartTime, request.EndTime)
+	}
+	key := sha256.Sum256([]byte(material))
+	return hex.EncodeToString(key[:]), nil
+}
+
+// lookupCachedResult returns a cached response for the request if an earlier
+// successful run used the same input and configuration and its output is
+// unchanged. The output is copied to the requested path when it differs.
+func (a *App) lookupCachedResult(request ProcessVideoRequest) (ProcessVideoResponse, bool) {
+	hit := func(ok bool) {
+		resultCacheCounters.Lock()
+		if ok {
+			resultCacheCounters.hits++
+		} else {
+			resultCacheCounters.misses++
+		}
+		resultCacheCounters.Unlock()
+	}
+
+	key, err := a.resultCacheKey(request)
+	if err != nil {
+		hit(false)
+		return ProcessVideoResponse{}, false
+	}
+	entry, err := readCachedResult(key)
+	if err != nil {
+		hit(false)
+		return ProcessVideoResponse{}, false
+	}
+	info, err := os.Stat(entry.OutputPath)
+	if err != nil || info.Size() != entry.OutputSize || !info.ModTime().Equal(entry.OutputTime) {
+		// The output was deleted or replaced since it was cached
+		removeCachedResult(key)
+		hit(false)
+		return ProcessVideoResponse{}, false
+	}
+
+	if filepath.Clean(entry.OutputPath) != filepath.Clean(request.OutputPath) {
+		a.unwatchOutputPath(request.OutputPath)
+		if err := copyOutputFile(entry.OutputPath, request.OutputPath, request.JobID); err != nil {
+			a.logger.Warn("failed to reuse cached output", map[string]interface{}{
+				"job_id":      request.JobID,
+				"cached_path": entry.OutputPath,
+				"error":       err.Error(),
+			})
+			hit(false)
+			return ProcessVideoResponse{}, false
+		}
+	}
+	hit(true)
+
+	response := entry.Response
+	// Entries stored before the database path was reported
+	if response.DatabaseID != "" && response.DatabasePath == "" {
+		response.DatabasePath = analysisDBPath(entry.InputPath)
+	}
+	response.OutputVideoPath = request.OutputPath
+	response.OutputWatchID = ""
+	response.Cached = true
+	response.Message = fmt.Sprintf("Reused the result of an earlier run on %s with the same configuration.", entry.StoredAt.Format("2006-01-02 15:04"))
+	return response, true
+}
+
+// storeCachedResult remembers a successful response for later lookups
+func (a *App) storeCachedResult(request ProcessVideoRequest, response ProcessVideoResponse) {
+	key, err := a.resultCacheKey(request)
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(response.OutputVideoPath)
+	if err != nil {
+		return
+	}
+	entry := cachedResult{
+		Key:        key,
+		InputPath:  request.InputPath,
+		OutputPath: response.OutputVideoPath,
+		OutputSize: info.Size(),
+		OutputTime: info.ModTime(),
+		Response:   response,
+		StoredAt:   time.Now(),
+	}
+	dir, err := resultCacheDir()
+	if err == nil {
+		var data []byte
+		data, err = json.MarshalIndent(entry, "", "  ")
+		if err == nil {
+			err = writeFileAtomic(filepath.Join(dir, key+".json"), data, 0644)
+		}
+	}
+	if err != nil {
+		a.logger.Warn("failed to cache result", map[string]interface{}{"job_id": request.JobID, "error": err.Error()})
+	}
+}
+
+// readCachedResult loads the cache entry for key
+func readCachedResult(key string) (cachedResult, error) {
+	dir, err := resultCacheDir()
+	if err != nil {
+		return cachedResult{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		return cachedResult{}, err
+	}
+	var entry cachedResult
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cachedResult{}, err
+	}
+	return entry, nil
+}
+
+// removeCachedResult deletes the cache entry for key
+func removeCachedResult(key string) {
+	if dir, err := resultCacheDir(); err == nil {
+		os.Remove(filepath.Join(dir, key+".json"))
+	}
+}
+
+// copyOutputFile copies a video to dst through a temporary file in the same
+// folder, so dst never holds a partial copy
+func copyOutputFile(src, dst, jobID string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	partialPath := partialOutputPath(dst, jobID)
+	out, err := os.Create(partialPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(partialPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(partialPath)
+		return err
+	}
+	if err := os.Rename(partialPath, dst); err != nil {
+		os.Remove(partialPath)
+		return err
+	}
+	return nil
+}
+
+// GetCacheStats returns the number of cached results and lookup counters
+func (a *App) GetCacheStats() (CacheStats, error) {
+	dir, err := resultCacheDir()
+	if err != nil {
+		return CacheStats{}, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return CacheStats{}, fmt.Errorf("failed to read cache directory %s: %v", dir, err)
+	}
+
+	var stats CacheStats
+	for _, file := range files {
+		key, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok {
+			continue
+		}
+		entry, err := readCachedResult(key)
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.OutputBytes += entry.OutputSize
+	}
+
+	resultCacheCounters.Lock()
+	stats.Hits = resultCacheCounters.hits
+	stats.Misses = resultCacheCounters.misses
+	resultCacheCounters.Unlock()
+	return stats, nil
+}
+
+// ClearCache forgets all cached results. The output videos themselves are kept.
+func (a *App) ClearCache() error {
+	dir, err := resultCacheDir()
+	if err != nil {
+		return err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory %s: %v", dir, err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".json") {
+			if err := os.Remove(filepath.Join(dir, file.Name())); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to clear cache: %v", err)
+			}
+		}
+	}
+	a.logger.Info("result cache cleared", nil)
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// maxRetryAttempts bounds the retry_attempts setting
+	maxRetryAttempts = 10
+	// maxRetryDelay caps the exponential backoff between attempts
+	maxRetryDelay = 5 * time.Minute
+	// retryCancelPollInterval is how often a waiting retry checks for cancellation
+	retryCancelPollInterval = 200 * time.Millisecond
+	// lowMemoryEnvVar asks the backend to trade accuracy for memory; it is set
+	// when retrying after the backend ran out of memory
+	lowMemoryEnvVar = "SUBKOMA_LOW_MEMORY"
+)
+
+// fileLockMarkers are fragments of error messages caused by another process
+// holding a file open, which usually clears up on its own
+var fileLockMarkers = []string{
+	"being used by another process",
+	"sharing violation",
+	"resource busy",
+	"resource temporarily unavailable",
+	"text file busy",
+	"is locked",
+}
+
+// isTransientFailure reports whether a failed run may succeed if repeated:
+// the backend crashed, ran out of memory, or hit a locked file
+func isTransientFailure(response ProcessVideoResponse) bool {
+	switch response.ErrorType {
+	case "BackendCrashError", "MemoryError":
+		return true
+	case "PermissionError", "FileAccessError", "FileSystemError", "OutputError":
+		message := strings.ToLower(response.Message)
+		for _, marker := range fileLockMarkers {
+			if strings.Contains(message, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryDelay returns the wait before the given retry (1 for the first),
+// doubling from base up to maxRetryDelay
+func retryDelay(base time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 1; i < retry && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// retryAttempts returns how many times a request may be retried
+func (a *App) retryAttempts(request ProcessVideoRequest) int {
+	switch {
+	case request.RetryAttempts < 0:
+		return 0
+	case request.RetryAttempts > 0:
+		return request.RetryAttempts
+	}
+	return a.GetSettings().RetryAttempts
+}
+
+// processWithRetries runs processVideo, repeating it after transient
+// failures with exponential backoff. Every failed attempt is recorded in the
+// history; the returned response carries the number of attempts made.
+func (a *App) processWithRetries(request ProcessVideoRequest) ProcessVideoResponse {
+	retries := a.retryAttempts(request)
+	baseDelay := time.Duration(a.GetSettings().RetryDelaySeconds) * time.Second
+
+	for attempt := 1; ; attempt++ {
+		startedAt := time.Now()
+		response := a.processVideo(request)
+		response.Attempts = attempt
+		if response.Status != "error" || !isTransientFailure(response) || attempt > retries {
+			return response
+		}
+
+		delay := retryDelay(baseDelay, attempt)
+		if request.Deadline != nil && time.Now().Add(delay).After(*request.Deadline) {
+			return response
+		}
+
+		response.JobID = request.JobID
+		a.recordHistory(request, response, startedAt)
+		a.logger.Warn("retrying after a transient failure", map[string]interface{}{
+			"job_id":     request.JobID,
+			"phase":      "retry",
+			"attempt":    attempt,
+			"error_type": response.ErrorType,
+			"delay":      delay.String(),
+		})
+		a.emit("processing:retrying", map[string]interface{}{
+			"job_id":        request.JobID,
+			"attempt":       attempt,
+			"max_attempts":  retries + 1,
+			"error_type":    response.ErrorType,
+			"message":       response.Message,
+			"delay_seconds": delay.Seconds(),
+		})
+
+		if response.ErrorType == "MemoryError" {
+			request = withLowMemoryHint(request)
+		}
+		if !a.waitForRetry(request.JobID, delay) {
+			return ProcessVideoResponse{
+				Status:   "cancelled",
+				JobID:    request.JobID,
+				Message:  "Processing was cancelled while waiting to retry.",
+				Attempts: attempt,
+			}
+		}
+	}
+}
+
+// withLowMemoryHint returns a copy of request that asks the backend to use
+// less memory. Setting an environment variable also moves the job to a fresh
+// backend process instead of the persistent worker.
+func withLowMemoryHint(request ProcessVideoRequest) ProcessVideoRequest {
+	env := make(map[string]string, len(request.Env)+1)
+	for key, value := range request.Env {
+		env[key] = value
+	}
+	env[lowMemoryEnvVar] = "1"
+	request.Env = env
+	return request
+}
+
+// waitForRetry sleeps for delay while keeping the job cancellable. It returns
+// false if the job was cancelled in the meantime.
+func (a *App) waitForRetry(jobID string, delay time.Duration) bool {
+	job := a.registerJob(jobID, "")
+	defer a.unregisterJob(jobID)
+
+	deadline := time.Now().Add(delay)
+	ticker := time.NewTicker(retryCancelPollInterval)
+	defer ticker.Stop()
+	for time.Now().Before(deadline) {
+		if a.isJobCancelled(job) {
+			return false
+		}
+		<-ticker.C
+	}
+	return !a.isJobCancelled(job)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// RevealInFileManager opens the folder containing path in the system file
+// manager, selecting the file where the platform supports it
+func (a *App) RevealInFileManager(path string) error {
+	absPath, err := existingPath(path)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("explorer", "/select,"+absPath)
+	case "darwin":
+		cmd = exec.Command("open", "-R", absPath)
+	default:
+		// xdg-open has no way to select a file, so open its folder
+		return openWithSystem(filepath.Dir(absPath))
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to reveal %s: %v", absPath, err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// OpenInDefaultPlayer opens a video with the application registered for its type
+func (a *App) OpenInDefaultPlayer(path string) error {
+	absPath, err := existingPath(path)
+	if err != nil {
+		return err
+	}
+	return openWithSystem(absPath)
+}
+
+// existingPath returns the absolute form of path, or an error if nothing exists there
+func existingPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %v", path, err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return "", fmt.Errorf("cannot access %s: %v", absPath, err)
+	}
+	return absPath, nil
+}
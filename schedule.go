@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxScheduleWait caps how long the queue sleeps before re-checking scheduled
+// jobs, so a suspended machine or a clock change does not delay them for long
+const maxScheduleWait = time.Minute
+
+// OffHoursSettings is the daily window in which jobs marked off_hours_only
+// may run. Times are "HH:MM" in local time; a start after the end wraps past
+// midnight.
+type OffHoursSettings struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// offHoursWindow is a parsed OffHoursSettings, in minutes after midnight
+type offHoursWindow struct {
+	start, end int
+}
+
+// parseClock parses an "HH:MM" time of day into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseOffHours validates and parses the off-hours window
+func parseOffHours(settings OffHoursSettings) (offHoursWindow, error) {
+	start, err := parseClock(settings.Start)
+	if err != nil {
+		return offHoursWindow{}, fmt.Errorf("off_hours start: %v", err)
+	}
+	end, err := parseClock(settings.End)
+	if err != nil {
+		return offHoursWindow{}, fmt.Errorf("off_hours end: %v", err)
+	}
+	if start == end {
+		return offHoursWindow{}, fmt.Errorf("off_hours start and end must differ")
+	}
+	return offHoursWindow{start: start, end: end}, nil
+}
+
+// contains reports whether t falls inside the window
+func (w offHoursWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+// nextStart returns the next time after t at which the window opens
+func (w offHoursWindow) nextStart(t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), w.start/60, w.start%60, 0, 0, t.Location())
+	if !start.After(t) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
+
+// SetOffHours changes the window in which off-hours jobs may start
+func (q *JobQueue) SetOffHours(window offHoursWindow) {
+	q.mu.Lock()
+	q.offHours = window
+	q.mu.Unlock()
+
+	q.dispatch()
+}
+
+// dueLocked reports whether a pending entry may start at now, and if not,
+// when it should be checked again; q.mu must be held
+func (q *JobQueue) dueLocked(entry *queueEntry, now time.Time) (bool, time.Time) {
+	if runAt := entry.request.RunAt; runAt != nil && now.Before(*runAt) {
+		return false, *runAt
+	}
+	if entry.request.OffHoursOnly && !q.offHours.contains(now) {
+		return false, q.offHours.nextStart(now)
+	}
+	return true, time.Time{}
+}
+
+// scheduleWakeLocked arranges for dispatch to run again at next, replacing
+// any earlier wake-up. A zero next cancels it. q.mu must be held.
+func (q *JobQueue) scheduleWakeLocked(next time.Time) {
+	if q.wake != nil {
+		q.wake.Stop()
+		q.wake = nil
+	}
+	if next.IsZero() {
+		return
+	}
+	wait := time.Until(next)
+	if wait > maxScheduleWait {
+		wait = maxScheduleWait
+	}
+	q.wake = time.AfterFunc(wait, q.dispatch)
+}
+
+// ScheduleJob adds request to the batch queue to start no earlier than runAt
+// and returns its job ID. The schedule is kept if the app restarts before then.
+func (a *App) ScheduleJob(request ProcessVideoRequest, runAt time.Time) (string, error) {
+	if runAt.IsZero() {
+		return "", fmt.Errorf("a start time is required")
+	}
+	request.RunAt = &runAt
+	jobID := a.jobQueue().Enqueue([]ProcessVideoRequest{request})[0]
+	a.logger.Info("job scheduled", map[string]interface{}{
+		"job_id":         jobID,
+		"run_at":         runAt,
+		"off_hours_only": request.OffHoursOnly,
+	})
+	return jobID, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns 2026-01-05 (a Monday) at the given local time
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 1, 5, hour, minute, 0, 0, time.Local)
+}
+
+func TestParseOffHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings OffHoursSettings
+		want     offHoursWindow
+		wantErr  bool
+	}{
+		{name: "overnight", settings: OffHoursSettings{Start: "22:00", End: "07:00"}, want: offHoursWindow{start: 22 * 60, end: 7 * 60}},
+		{name: "daytime", settings: OffHoursSettings{Start: "09:30", End: "17:15"}, want: offHoursWindow{start: 9*60 + 30, end: 17*60 + 15}},
+		{name: "bad start", settings: OffHoursSettings{Start: "25:00", End: "07:00"}, wantErr: true},
+		{name: "bad end", settings: OffHoursSettings{Start: "22:00", End: "7pm"}, wantErr: true},
+		{name: "empty window", settings: OffHoursSettings{Start: "08:00", End: "08:00"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOffHours(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOffHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseOffHours() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffHoursWindow(t *testing.T) {
+	overnight := offHoursWindow{start: 22 * 60, end: 7 * 60}
+	daytime := offHoursWindow{start: 9 * 60, end: 17 * 60}
+
+	tests := []struct {
+		name          string
+		window        offHoursWindow
+		now           time.Time
+		wantContains  bool
+		wantNextStart time.Time
+	}{
+		{name: "overnight before start", window: overnight, now: at(21, 59), wantContains: false, wantNextStart: at(22, 0)},
+		{name: "overnight at start", window: overnight, now: at(22, 0), wantContains: true, wantNextStart: at(22, 0).AddDate(0, 0, 1)},
+		{name: "overnight after midnight", window: overnight, now: at(3, 0), wantContains: true, wantNextStart: at(22, 0)},
+		{name: "overnight at end", window: overnight, now: at(7, 0), wantContains: false, wantNextStart: at(22, 0)},
+		{name: "daytime inside", window: daytime, now: at(12, 0), wantContains: true, wantNextStart: at(9, 0).AddDate(0, 0, 1)},
+		{name: "daytime before", window: daytime, now: at(8, 59), wantContains: false, wantNextStart: at(9, 0)},
+		{name: "daytime after", window: daytime, now: at(17, 0), wantContains: false, wantNextStart: at(9, 0).AddDate(0, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.contains(tt.now); got != tt.wantContains {
+				t.Errorf("contains(%s) = %v, want %v", tt.now.Format("15:04"), got, tt.wantContains)
+			}
+			if got := tt.window.nextStart(tt.now); !got.Equal(tt.wantNextStart) {
+				t.Errorf("nextStart(%s) = %s, want %s", tt.now.Format("15:04"), got, tt.wantNextStart)
+			}
+		})
+	}
+}
+
+func TestJobQueueDue(t *testing.T) {
+	now := at(12, 0)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+	queue := NewJobQueue(1, nil, nil)
+	queue.offHours = offHoursWindow{start: 22 * 60, end: 7 * 60}
+
+	tests := []struct {
+		name    string
+		request ProcessVideoRequest
+		wantDue bool
+		wantAt  time.Time
+	}{
+		{name: "unscheduled", request: ProcessVideoRequest{}, wantDue: true},
+		{name: "run at in the past", request: ProcessVideoRequest{RunAt: &earlier}, wantDue: true},
+		{name: "run at in the future", request: ProcessVideoRequest{RunAt: &later}, wantAt: later},
+		{name: "off hours outside the window", request: ProcessVideoRequest{OffHoursOnly: true}, wantAt: at(22, 0)},
+		{name: "run at before the window opens", request: ProcessVideoRequest{RunAt: &later, OffHoursOnly: true}, wantAt: later},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, next := queue.dueLocked(&queueEntry{request: tt.request}, now)
+			if due != tt.wantDue || !next.Equal(tt.wantAt) {
+				t.Errorf("dueLocked() = %v, %s, want %v, %s", due, next, tt.wantDue, tt.wantAt)
+			}
+		})
+	}
+
+	t.Run("off hours inside the window", func(t *testing.T) {
+		if due, _ := queue.dueLocked(&queueEntry{request: ProcessVideoRequest{OffHoursOnly: true}}, at(23, 0)); !due {
+			t.Error("dueLocked() = false inside the off-hours window, want true")
+		}
+	})
+}
+
+func TestJobQueueHoldsScheduledJobs(t *testing.T) {
+	processor := newBlockingProcessor("success")
+	close(processor.release)
+	queue := NewJobQueue(2, processor.process, nil)
+	// A window that excludes the current time, whenever the test runs
+	now := time.Now()
+	start := (now.Hour()*60 + now.Minute() + 120) % (24 * 60)
+	queue.SetOffHours(offHoursWindow{start: start, end: (start + 60) % (24 * 60)})
+
+	runAt := now.Add(time.Hour)
+	requests := requestsWithIDs("scheduled", "off-hours", "now")
+	requests[0].RunAt = &runAt
+	requests[1].OffHoursOnly = true
+	queue.Enqueue(requests)
+
+	waitFor(t, "the unscheduled job to finish", func() bool { return queue.State().Done == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if got := processor.startedJobs(); len(got) != 1 || got[0] != "now" {
+		t.Errorf("started = %q, want only the unscheduled job", got)
+	}
+	if pending := queue.State().Pending; pending != 2 {
+		t.Errorf("pending = %d, want 2", pending)
+	}
+}
+
+func TestScheduleJob(t *testing.T) {
+	app := newTestApp(t)
+
+	if _, err := app.ScheduleJob(ProcessVideoRequest{InputPath: "in.mp4"}, time.Time{}); err == nil {
+		t.Error("ScheduleJob() with a zero time succeeded, want an error")
+	}
+
+	runAt := time.Now().Add(time.Hour)
+	jobID, err := app.ScheduleJob(ProcessVideoRequest{InputPath: "in.mp4", OutputPath: "out.mp4"}, runAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, ok := app.queuedJob(jobID)
+	if !ok {
+		t.Fatalf("scheduled job %s not in the queue", jobID)
+	}
+	if job.State != jobStatePending || job.RunAt == nil || !job.RunAt.Equal(runAt) {
+		t.Errorf("job = %+v, want pending with run_at %s", job, runAt)
+	}
+
+	// The schedule is part of the saved queue, so it survives a restart
+	pending := app.jobQueue().PendingRequests()
+	if len(pending) != 1 || pending[0].RunAt == nil || !pending[0].RunAt.Equal(runAt) {
+		t.Errorf("pending requests = %+v, want the job with its run_at", pending)
+	}
+}
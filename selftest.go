@@ -0,0 +1,154 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// selfTestSample is a two-second 160x120 MJPEG clip of a simple figure
+// walking, small enough to process in seconds
+//
+//go:embed assets/selftest_sample.avi
+var selfTestSample []byte
+
+// SelfTestStage is the outcome of one step of RunSelfTest
+type SelfTestStage struct {
+	Name       string `json:"name"`
+	Passed     bool   `json:"passed"`
+	Skipped    bool   `json:"skipped,omitempty"` // not run because an earlier stage failed
+	Message    string `json:"message"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SelfTestReport is the result of RunSelfTest, sent with "selftest:completed" events
+type SelfTestReport struct {
+	Passed     bool            `json:"passed"`
+	Stages     []SelfTestStage `json:"stages"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+}
+
+// RunSelfTest processes a bundled sample clip through the real pipeline and
+// reports which stage, if any, failed. It bypasses the result cache, history
+// and notifications, and removes its files afterwards. Each stage is also
+// emitted as a "selftest:stage" event as it finishes.
+func (a *App) RunSelfTest() SelfTestReport {
+	report := SelfTestReport{Passed: true, StartedAt: time.Now()}
+	a.logger.Info("self-test started", nil)
+
+	dir, err := os.MkdirTemp("", "subkoma-selftest-")
+	if err != nil {
+		dir = ""
+	}
+	inputPath := filepath.Join(dir, "sample.avi")
+	outputPath := filepath.Join(dir, "sample_output.mp4")
+	defer func() {
+		if dir != "" {
+			a.unwatchOutputPath(outputPath)
+			os.RemoveAll(dir)
+		}
+	}()
+	var response ProcessVideoResponse
+
+	stages := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{"environment", func() (string, error) {
+			env := a.CheckEnvironment()
+			if !env.OK {
+				var failed []string
+				for _, check := range env.Checks {
+					if check.Required && !check.OK {
+						failed = append(failed, fmt.Sprintf("%s (%s)", check.Name, check.Detail))
+					}
+				}
+				return "", fmt.Errorf("required checks failed: %s", strings.Join(failed, "; "))
+			}
+			return "Required tools and Python packages are installed.", nil
+		}},
+		{"backend_version", func() (string, error) {
+			version, err := a.GetBackendVersion()
+			if err != nil {
+				return "", err
+			}
+			if _, err := a.checkBackendVersion(version); err != nil {
+				return "", err
+			}
+			return "Backend version " + version + " is supported.", nil
+		}},
+		{"sample", func() (string, error) {
+			if dir == "" {
+				return "", fmt.Errorf("failed to create a temporary directory: %v", err)
+			}
+			if err := os.WriteFile(inputPath, selfTestSample, 0644); err != nil {
+				return "", fmt.Errorf("failed to write the sample clip: %v", err)
+			}
+			return fmt.Sprintf("Sample clip written to %s.", inputPath), nil
+		}},
+		{"processing", func() (string, error) {
+			response = a.processVideo(ProcessVideoRequest{
+				InputPath:   inputPath,
+				OutputPath:  outputPath,
+				Config:      "{}",
+				Description: "Self-test",
+				JobID:       generateID(),
+			})
+			if response.Status != "success" {
+				return "", fmt.Errorf("%s: %s", response.ErrorType, response.Message)
+			}
+			return "The backend processed the sample clip.", nil
+		}},
+		{"output", func() (string, error) {
+			info, err := os.Stat(outputPath)
+			if err != nil {
+				return "", fmt.Errorf("output video was not created: %v", err)
+			}
+			if info.Size() == 0 {
+				return "", fmt.Errorf("output video is empty")
+			}
+			return fmt.Sprintf("Output video written (%s).", formatBytes(uint64(info.Size()))), nil
+		}},
+		{"database", func() (string, error) {
+			if id, err := strconv.Atoi(response.DatabaseID); err != nil || id < 1 {
+				return "", fmt.Errorf("the backend returned an invalid database ID %q", response.DatabaseID)
+			}
+			return "Analysis saved with database ID " + response.DatabaseID + ".", nil
+		}},
+	}
+
+	for _, stage := range stages {
+		result := SelfTestStage{Name: stage.name}
+		if !report.Passed {
+			result.Skipped = true
+			result.Message = "Skipped because an earlier stage failed."
+		} else {
+			started := time.Now()
+			message, err := stage.run()
+			result.DurationMs = time.Since(started).Milliseconds()
+			if err != nil {
+				result.Message = err.Error()
+				report.Passed = false
+			} else {
+				result.Passed = true
+				result.Message = message
+			}
+		}
+		report.Stages = append(report.Stages, result)
+		a.emit("selftest:stage", result)
+	}
+
+	report.FinishedAt = time.Now()
+	a.emit("selftest:completed", report)
+	if report.Passed {
+		a.logger.Info("self-test passed", nil)
+	} else {
+		a.logger.Error("self-test failed", map[string]interface{}{"stages": report.Stages})
+	}
+	return report
+}
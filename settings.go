@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Settings are the user preferences persisted between sessions
+type Settings struct {
+	// DefaultOutputDir is where the output dialog opens. Empty leaves the choice to the dialog.
+	DefaultOutputDir   string `json:"default_output_dir"`
+	QueueConcurrency   int    `json:"queue_concurrency"`
+	JobTimeoutSeconds  int    `json:"job_timeout_seconds"` // zero disables the timeout
+	NotifyOnCompletion bool   `json:"notify_on_completion"`
+	// LowPriority runs the backend below normal CPU and I/O priority so the
+	// machine stays responsive while processing
+	LowPriority bool `json:"low_priority"`
+	// MaxThreads caps the threads each backend process uses. Zero leaves the
+	// choice to the libraries, which usually take every core.
+	MaxThreads int `json:"max_threads"`
+	// Device pins the backend to an accelerator from DetectGPUs, such as
+	// "cuda:0", or to "cpu". Empty lets the backend choose.
+	Device string `json:"device"`
+	// RetryAttempts is how many times a job is retried after a transient
+	// failure such as a backend crash; RetryDelaySeconds is the wait before
+	// the first retry, doubled for each one after it
+	RetryAttempts     int `json:"retry_attempts"`
+	RetryDelaySeconds int `json:"retry_delay_seconds"`
+	// BackendPath is the folder containing process_video.py. Empty resolves
+	// it as described on backendDir.
+	BackendPath string `json:"backend_path"`
+	// Interpreter selects how the backend script is run: "uv" (the default),
+	// "python" for the executable at PythonPath, or "venv" for the one in VenvPath
+	Interpreter string        `json:"interpreter"`
+	PythonPath  string        `json:"python_path"` // empty uses python from PATH
+	VenvPath    string        `json:"venv_path"`
+	Debug       DebugSettings `json:"debug"`
+	// API controls the localhost HTTP API that lets other tools submit jobs
+	API APIServerSettings `json:"api"`
+	// OffHours is when queued jobs marked off_hours_only may run
+	OffHours OffHoursSettings `json:"off_hours"`
+	// HistoryRetention limits the processing history, trimmed at startup
+	HistoryRetention HistoryRetentionSettings `json:"history_retention"`
+}
+
+// HistoryRetentionSettings limit how much processing history is kept. Zero
+// disables a limit.
+type HistoryRetentionSettings struct {
+	MaxEntries int `json:"max_entries"`
+	MaxAgeDays int `json:"max_age_days"`
+}
+
+// DebugSettings control running the backend under debugpy
+type DebugSettings struct {
+	PythonDebug   bool   `json:"python_debug"`
+	WaitForClient bool   `json:"wait_for_client"` // block the backend until a debugger attaches
+	Port          string `json:"port"`            // empty uses the backend's default port
+}
+
+// DefaultSettings returns the settings used before the user changes anything
+func DefaultSettings() Settings {
+	config := DefaultAppConfig()
+	return Settings{
+		QueueConcurrency:   config.QueueConcurrency,
+		JobTimeoutSeconds:  config.JobTimeoutSeconds,
+		NotifyOnCompletion: config.NotifyOnCompletion,
+		Interpreter:        interpreterUV,
+		API:                APIServerSettings{Port: defaultAPIPort},
+		OffHours:           OffHoursSettings{Start: "22:00", End: "07:00"},
+		RetryAttempts:      2,
+		RetryDelaySeconds:  5,
+	}
+}
+
+// Validate checks that the settings can be applied
+func (s Settings) Validate() error {
+	if s.QueueConcurrency < 1 {
+		return fmt.Errorf("queue_concurrency must be at least 1, got %d", s.QueueConcurrency)
+	}
+	if s.JobTimeoutSeconds < 0 {
+		return fmt.Errorf("job_timeout_seconds must not be negative, got %d", s.JobTimeoutSeconds)
+	}
+	if s.RetryAttempts < 0 || s.RetryAttempts > maxRetryAttempts {
+		return fmt.Errorf("retry_attempts must be between 0 and %d, got %d", maxRetryAttempts, s.RetryAttempts)
+	}
+	if s.RetryDelaySeconds < 0 {
+		return fmt.Errorf("retry_delay_seconds must not be negative, got %d", s.RetryDelaySeconds)
+	}
+	if s.MaxThreads < 0 {
+		return fmt.Errorf("max_threads must not be negative, got %d", s.MaxThreads)
+	}
+	if _, _, err := parseDevice(s.Device); err != nil {
+		return err
+	}
+	if s.DefaultOutputDir != "" {
+		if info, err := os.Stat(s.DefaultOutputDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("default_output_dir is not a directory: %s", s.DefaultOutputDir)
+		}
+	}
+	if s.BackendPath != "" {
+		if _, err := os.Stat(filepath.Join(s.BackendPath, "process_video.py")); err != nil {
+			return fmt.Errorf("backend_path does not contain process_video.py: %s", s.BackendPath)
+		}
+	}
+	if err := validateInterpreter(s); err != nil {
+		return err
+	}
+	if s.Debug.Port != "" {
+		if port, err := strconv.Atoi(s.Debug.Port); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("debug port must be a number between 1 and 65535, got %q", s.Debug.Port)
+		}
+	}
+	if s.HistoryRetention.MaxEntries < 0 {
+		return fmt.Errorf("history max_entries must not be negative, got %d", s.HistoryRetention.MaxEntries)
+	}
+	if s.HistoryRetention.MaxAgeDays < 0 {
+		return fmt.Errorf("history max_age_days must not be negative, got %d", s.HistoryRetention.MaxAgeDays)
+	}
+	if _, err := parseOffHours(s.OffHours); err != nil {
+		return err
+	}
+	if s.API.Port < 1 || s.API.Port > 65535 {
+		return fmt.Errorf("api port must be between 1 and 65535, got %d", s.API.Port)
+	}
+	return nil
+}
+
+// settingsPath returns the location of the persisted settings
+func settingsPath() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// loadSettings applies the settings saved by a previous session. Missing
+// fields keep their defaults; an unreadable or invalid file is ignored.
+func (a *App) loadSettings() {
+	path, err := settingsPath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	settings := DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		a.logger.Warn("ignoring unreadable settings", map[string]interface{}{"path": path, "error": err.Error()})
+		return
+	}
+	if err := settings.Validate(); err != nil {
+		a.logger.Warn("ignoring invalid settings", map[string]interface{}{"path": path, "error": err.Error()})
+		return
+	}
+	a.applySettings(settings)
+}
+
+// saveSettings writes settings to disk, replacing the previous file atomically
+func saveSettings(settings Settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %v", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write settings: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write settings: %v", err)
+	}
+	return nil
+}
+
+// applySettings makes settings the current ones and updates the state that depends on them
+func (a *App) applySettings(settings Settings) {
+	a.mu.Lock()
+	a.settings = settings
+	a.config.QueueConcurrency = settings.QueueConcurrency
+	a.config.JobTimeoutSeconds = settings.JobTimeoutSeconds
+	a.config.NotifyOnCompletion = settings.NotifyOnCompletion
+	queue := a.queue
+	a.mu.Unlock()
+
+	if queue != nil {
+		_ = queue.SetConcurrency(settings.QueueConcurrency)
+		if window, err := parseOffHours(settings.OffHours); err == nil {
+			queue.SetOffHours(window)
+		}
+	}
+}
+
+// GetSettings returns the current settings
+func (a *App) GetSettings() Settings {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.settings
+}
+
+// UpdateSettings validates, saves and applies new settings, returning them as
+// stored. Changing where or how the backend runs restarts the persistent
+// backend worker.
+func (a *App) UpdateSettings(settings Settings) (Settings, error) {
+	for _, path := range []*string{&settings.DefaultOutputDir, &settings.BackendPath, &settings.VenvPath} {
+		if *path == "" {
+			continue
+		}
+		absPath, err := filepath.Abs(*path)
+		if err != nil {
+			return a.GetSettings(), fmt.Errorf("invalid path %s: %v", *path, err)
+		}
+		*path = absPath
+	}
+	if err := settings.Validate(); err != nil {
+		return a.GetSettings(), err
+	}
+	if err := saveSettings(settings); err != nil {
+		return a.GetSettings(), err
+	}
+
+	previous := a.GetSettings()
+	a.applySettings(settings)
+	a.logger.Info("settings updated", nil)
+	a.emit("settings:updated", settings)
+
+	if settings.BackendPath != previous.BackendPath || settings.Interpreter != previous.Interpreter ||
+		settings.PythonPath != previous.PythonPath || settings.VenvPath != previous.VenvPath ||
+		settings.LowPriority != previous.LowPriority || settings.MaxThreads != previous.MaxThreads ||
+		settings.Device != previous.Device {
+		a.restartBackendWorker()
+	}
+	if settings.API != previous.API {
+		a.syncAPIServer()
+	}
+	return settings, nil
+}
+
+// debugSettings returns the current backend debug settings
+func (a *App) debugSettings() DebugSettings {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.settings.Debug
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Subtitle formats accepted by ExportSubtitles
+const (
+	subtitleFormatSRT = "srt"
+	subtitleFormatASS = "ass"
+	subtitleFormatVTT = "vtt"
+)
+
+// subtitleCue is one timed text entry
+type subtitleCue struct {
+	start, end float64 // seconds
+	text       string
+}
+
+// timingCues turns the per-frame motion states of an analysis into one cue
+// per run of frames with the same state, e.g. "HIGH motion - on 2s"
+func timingCues(frames []AnalysisFrame) []subtitleCue {
+	var cues []subtitleCue
+	for i, frame := range frames {
+		if i > 0 && frame.MotionState == frames[i-1].MotionState {
+			continue
+		}
+		if len(cues) > 0 {
+			cues[len(cues)-1].end = frame.Timestamp
+		}
+		text := frame.MotionState + " motion"
+		if hold, ok := motionStateHolds[frame.MotionState]; ok {
+			text = fmt.Sprintf("%s - on %ds", text, hold)
+		}
+		cues = append(cues, subtitleCue{start: frame.Timestamp, text: text})
+	}
+	if len(cues) > 0 {
+		cues[len(cues)-1].end = frames[len(frames)-1].Timestamp + analysisFrameDuration(frames)
+	}
+	return cues
+}
+
+// ExportSubtitles writes the timing decisions of the analysis databaseID in
+// the database file at databasePath, as reported in ProcessVideoResponse, as
+// a subtitle file in format "srt", "ass" or "vtt", so they can be
+// reviewed over the video in any player or editor. The backend analyses
+// motion, not on-screen text: each cue covers a run of frames with the same
+// motion state and names the frame timing chosen for it.
+func (a *App) ExportSubtitles(databasePath string, databaseID string, format string, outPath string) error {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	var render func([]subtitleCue) string
+	switch format {
+	case subtitleFormatSRT:
+		render = renderSRT
+	case subtitleFormatASS:
+		render = renderASS
+	case subtitleFormatVTT:
+		render = renderVTT
+	default:
+		return fmt.Errorf("unsupported subtitle format %q, expected srt, ass or vtt", format)
+	}
+	if outPath == "" {
+		return fmt.Errorf("an output path is required")
+	}
+	absPath, err := filepath.Abs(outPath)
+	if err != nil {
+		return fmt.Errorf("invalid output path %s: %v", outPath, err)
+	}
+
+	record, err := a.findAnalysisRecord(databasePath, databaseID)
+	if err != nil {
+		return err
+	}
+	if len(record.FrameData) == 0 {
+		return fmt.Errorf("analysis %s has no frame data", databaseID)
+	}
+
+	if err := writeFileAtomic(absPath, []byte(render(timingCues(record.FrameData))), 0644); err != nil {
+		return fmt.Errorf("failed to write subtitles: %v", err)
+	}
+	a.logger.Info("subtitles exported", map[string]interface{}{"database_id": databaseID, "format": format, "path": absPath})
+	return nil
+}
+
+// splitSeconds splits a time into whole hours, minutes, seconds and milliseconds
+func splitSeconds(seconds float64) (int, int, int, int) {
+	if seconds < 0 {
+		seconds = 0
+	}
+	total := int64(seconds*1000 + 0.5)
+	return int(total / 3600000), int(total / 60000 % 60), int(total / 1000 % 60), int(total % 1000)
+}
+
+// srtTime formats a time as HH:MM:SS,mmm
+func srtTime(seconds float64) string {
+	h, m, s, ms := splitSeconds(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+// vttTime formats a time as HH:MM:SS.mmm
+func vttTime(seconds float64) string {
+	h, m, s, ms := splitSeconds(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
+
+// assTime formats a time as H:MM:SS.cc, the centisecond precision of ASS
+func assTime(seconds float64) string {
+	h, m, s, ms := splitSeconds(seconds)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, ms/10)
+}
+
+// cueLines splits cue text into lines, dropping blank ones, which would end
+// an SRT or WebVTT cue early
+func cueLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// renderSRT formats cues as SubRip
+func renderSRT(cues []subtitleCue) string {
+	var b strings.Builder
+	for i, cue := range cues {
+		// "-->" inside the text would be read as a timing line
+		text := strings.ReplaceAll(strings.Join(cueLines(cue.text), "\n"), "-->", "->")
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, srtTime(cue.start), srtTime(cue.end), text)
+	}
+	return b.String()
+}
+
+// vttEscaper escapes the characters WebVTT cue text reserves for markup
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// renderVTT formats cues as WebVTT
+func renderVTT(cues []subtitleCue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for i, cue := range cues {
+		text := vttEscaper.Replace(strings.Join(cueLines(cue.text), "\n"))
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, vttTime(cue.start), vttTime(cue.end), text)
+	}
+	return b.String()
+}
+
+// assEscaper keeps ASS from reading text as override blocks or escape
+// sequences. A word joiner after a backslash stops \N, \n and \h from being
+// interpreted while rendering nothing visible.
+var assEscaper = strings.NewReplacer(`\`, "\\⁠", "{", `\{`, "}", `\}`)
+
+// assHeader declares a plain white style anchored at the top left, so the
+// cues stay clear of any burned-in subtitles
+const assHeader = `[Script Info]
+ScriptType: v4.00+
+PlayResX: 384
+PlayResY: 288
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,16,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,1,0,7,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+// renderASS formats cues as Advanced SubStation Alpha
+func renderASS(cues []subtitleCue) string {
+	var b strings.Builder
+	b.WriteString(assHeader)
+	for _, cue := range cues {
+		lines := cueLines(cue.text)
+		for i, line := range lines {
+			lines[i] = assEscaper.Replace(line)
+		}
+		fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", assTime(cue.start), assTime(cue.end), strings.Join(lines, `\N`))
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// thumbnailWidth is the width of generated thumbnails; the height keeps the aspect ratio
+const thumbnailWidth = 320
+
+// Thumbnail is a preview frame extracted from a video
+type Thumbnail struct {
+	Path    string `json:"path"`     // cached JPEG file
+	DataURL string `json:"data_url"` // the same image as a base64 data URL
+}
+
+// GenerateThumbnail extracts the frame at timestampSec as a JPEG preview.
+// Thumbnails are cached per file version and timestamp, so repeated calls for
+// unchanged inputs and history entries do not run ffmpeg again.
+func (a *App) GenerateThumbnail(path string, timestampSec float64) (Thumbnail, error) {
+	if timestampSec < 0 {
+		return Thumbnail{}, fmt.Errorf("timestamp must not be negative")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return Thumbnail{}, fmt.Errorf("invalid video path %s: %v", path, err)
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return Thumbnail{}, fmt.Errorf("cannot access video file: %s. Error: %v", absPath, err)
+	}
+
+	dir, err := appCacheDir("thumbnails")
+	if err != nil {
+		return Thumbnail{}, err
+	}
+	// A changed size or modification time means the file was replaced
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%.3f", absPath, info.Size(), info.ModTime().UnixNano(), timestampSec)))
+	thumbPath := filepath.Join(dir, hex.EncodeToString(key[:16])+".jpg")
+
+	if _, err := os.Stat(thumbPath); os.IsNotExist(err) {
+		tmpPath := thumbPath + ".tmp.jpg"
+		err := runFFmpeg("-y", "-v", "error",
+			"-ss", strconv.FormatFloat(timestampSec, 'f', 3, 64),
+			"-i", absPath,
+			"-frames:v", "1",
+			"-vf", fmt.Sprintf("scale=%d:-2", thumbnailWidth),
+			"-q:v", "4",
+			tmpPath)
+		if err != nil {
+			os.Remove(tmpPath)
+			return Thumbnail{}, err
+		}
+		// ffmpeg succeeds without writing a frame when the timestamp is past the end
+		if _, err := os.Stat(tmpPath); err != nil {
+			return Thumbnail{}, fmt.Errorf("no frame at %.3fs in %s; the timestamp may be past the end of the video", timestampSec, absPath)
+		}
+		if err := os.Rename(tmpPath, thumbPath); err != nil {
+			os.Remove(tmpPath)
+			return Thumbnail{}, fmt.Errorf("failed to cache thumbnail: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(thumbPath)
+	if err != nil {
+		return Thumbnail{}, fmt.Errorf("failed to read thumbnail: %v", err)
+	}
+
+	return Thumbnail{
+		Path:    thumbPath,
+		DataURL: "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
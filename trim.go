@@ -0,0 +1,39 @@
+package main
+
+import "fmt"
+
+// checkTrimRange validates the segment requested with StartTime and EndTime
+// against the probed duration of the input. It returns an error response, or
+// nil when the range is valid or not set. The duration check is skipped when
+// the input cannot be probed; the backend then rejects an empty segment.
+func checkTrimRange(request ProcessVideoRequest) *ProcessVideoResponse {
+	if request.StartTime == 0 && request.EndTime == 0 {
+		return nil
+	}
+	invalid := func(format string, args ...interface{}) *ProcessVideoResponse {
+		return &ProcessVideoResponse{
+			Status:    "error",
+			ErrorType: "ValidationError",
+			Message:   fmt.Sprintf(format, args...),
+		}
+	}
+
+	if request.StartTime < 0 || request.EndTime < 0 {
+		return invalid("Start and end times must not be negative.")
+	}
+	if request.EndTime > 0 && request.EndTime <= request.StartTime {
+		return invalid("End time %.3fs must be after start time %.3fs.", request.EndTime, request.StartTime)
+	}
+
+	duration, err := probeDuration(request.InputPath)
+	if err != nil {
+		return nil
+	}
+	if request.StartTime >= duration {
+		return invalid("Start time %.3fs is not before the end of the video (%.3fs).", request.StartTime, duration)
+	}
+	if request.EndTime > duration {
+		return invalid("End time %.3fs is past the end of the video (%.3fs).", request.EndTime, duration)
+	}
+	return nil
+}
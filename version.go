@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The range of backend versions whose interface this build understands: any
+// 1.x release of backend/process_video.py
+const (
+	minSupportedBackendVersion = "1.0.0"
+	maxSupportedBackendVersion = "1.999.999"
+)
+
+// ParseSemver parses a version string of the form "v1.2.3" or "1.2.3".
+// Pre-release and build suffixes ("-rc.1", "+build") are ignored.
+func ParseSemver(v string) (major, minor, patch int, err error) {
+	s := strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid semantic version %q", v)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid semantic version %q", v)
+		}
+		numbers[i] = n
+	}
+
+	return numbers[0], numbers[1], numbers[2], nil
+}
+
+// compareSemver returns -1, 0 or 1 depending on whether a is lower than,
+// equal to or higher than b
+func compareSemver(a, b string) (int, error) {
+	aMajor, aMinor, aPatch, err := ParseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	bMajor, bMinor, bPatch, err := ParseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range []int{aMajor - bMajor, aMinor - bMinor, aPatch - bPatch} {
+		if d < 0 {
+			return -1, nil
+		}
+		if d > 0 {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// checkBackendVersion compares a version reported by the backend against the
+// configured bounds. It returns the ErrorType to report ("VersionTooOld" or
+// "VersionTooNew") together with an error, or an empty type when the version is accepted.
+func (a *App) checkBackendVersion(version string) (string, error) {
+	if minVersion := a.config.MinBackendVersion; minVersion != "" {
+		cmp, err := compareSemver(version, minVersion)
+		if err != nil {
+			return "VersionParseError", err
+		}
+		if cmp < 0 {
+			return "VersionTooOld", fmt.Errorf("backend version %s is older than the minimum supported version %s", version, minVersion)
+		}
+	}
+
+	if maxVersion := a.config.MaxBackendVersion; maxVersion != "" {
+		cmp, err := compareSemver(version, maxVersion)
+		if err != nil {
+			return "VersionParseError", err
+		}
+		if cmp > 0 {
+			return "VersionTooNew", fmt.Errorf("backend version %s is newer than the maximum supported version %s", version, maxVersion)
+		}
+	}
+
+	return "", nil
+}
+
+// versionProbe is a cached GetBackendVersion result
+type versionProbe struct {
+	key     string // script path, modification time and interpreter
+	version string
+}
+
+// GetBackendVersion runs the backend script with --version and returns the
+// semantic version it prints. The result is cached until the script file or
+// the interpreter changes.
+func (a *App) GetBackendVersion() (string, error) {
+	backendDir := a.backendDir()
+	scriptPath := filepath.Join(backendDir, backendScriptName)
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		return "", fmt.Errorf("backend script not found: %s", scriptPath)
+	}
+	python, args := a.pythonCommand(backendScriptName, "--version")
+	key := fmt.Sprintf("%s|%d|%s %s", scriptPath, info.ModTime().UnixNano(), python, strings.Join(args, " "))
+
+	a.mu.RLock()
+	cached := a.backendVersion
+	a.mu.RUnlock()
+	if cached != nil && cached.key == key {
+		return cached.version, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, python, args...)
+	cmd.Dir = backendDir
+	cmd.Env = a.backendEnv(ProcessVideoRequest{})
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to query the backend version: %v: %s", err, stderrExcerpt(strings.TrimSpace(stderr.String())))
+	}
+
+	// Anything the interpreter launcher prints comes before the version
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	version := strings.TrimSpace(lines[len(lines)-1])
+	if _, _, _, err := ParseSemver(version); err != nil {
+		return "", fmt.Errorf("backend reported an invalid version: %v", err)
+	}
+
+	a.mu.Lock()
+	a.backendVersion = &versionProbe{key: key, version: version}
+	a.mu.Unlock()
+	return version, nil
+}
+
+// checkBackendCompatibility returns a BackendVersionMismatch response when the
+// backend reports a version outside the supported range, and nil otherwise.
+// A backend whose version cannot be read is let through; it fails with a more
+// specific error, such as missing dependencies, once it runs.
+func (a *App) checkBackendCompatibility() *ProcessVideoResponse {
+	version, err := a.GetBackendVersion()
+	if err != nil {
+		a.logger.Warn("backend version check skipped", map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+	errorType, err := a.checkBackendVersion(version)
+	if errorType == "" {
+		return nil
+	}
+
+	hint := "Update the backend: reinstall subkoma, or clear the backend path in the settings to use the bundled backend."
+	if errorType == "VersionTooNew" {
+		hint = "Update subkoma to a release that supports this backend, or clear the backend path in the settings to use the bundled backend."
+	}
+	a.logger.Error("incompatible backend version", map[string]interface{}{
+		"version": version,
+		"reason":  errorType,
+		"error":   err.Error(),
+	})
+	return &ProcessVideoResponse{
+		Status:    "error",
+		ErrorType: "BackendVersionMismatch",
+		Message:   fmt.Sprintf("The backend is incompatible with this version of subkoma: %v. %s", err, hint),
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		version                         string
+		wantMajor, wantMinor, wantPatch int
+		wantErr                         bool
+	}{
+		{version: "1.2.3", wantMajor: 1, wantMinor: 2, wantPatch: 3},
+		{version: "v1.2.3", wantMajor: 1, wantMinor: 2, wantPatch: 3},
+		{version: "1.2.3-rc1", wantMajor: 1, wantMinor: 2, wantPatch: 3},
+		{version: "v1.2.3-rc.1+build.5", wantMajor: 1, wantMinor: 2, wantPatch: 3},
+		{version: " 10.20.30\n", wantMajor: 10, wantMinor: 20, wantPatch: 30},
+		{version: "", wantErr: true},
+		{version: "1.2", wantErr: true},
+		{version: "1.2.3.4", wantErr: true},
+		{version: "1.x.3", wantErr: true},
+		{version: "-1.2.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			major, minor, patch, err := ParseSemver(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSemver(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+			if !tt.wantErr && (major != tt.wantMajor || minor != tt.wantMinor || patch != tt.wantPatch) {
+				t.Errorf("ParseSemver(%q) = %d.%d.%d, want %d.%d.%d", tt.version, major, minor, patch, tt.wantMajor, tt.wantMinor, tt.wantPatch)
+			}
+		})
+	}
+}
+
+func TestCheckBackendVersion(t *testing.T) {
+	app := NewAppWithLogger(NewJSONLogger(io.Discard))
+	app.config.MinBackendVersion = minSupportedBackendVersion
+	app.config.MaxBackendVersion = maxSupportedBackendVersion
+
+	tests := []struct {
+		version  string
+		wantType string
+	}{
+		{version: "1.0.0"},
+		{version: "v1.0.0"},
+		{version: "1.0.0-rc1"},
+		{version: "1.999.999"},
+		{version: "0.999.999", wantType: "VersionTooOld"},
+		{version: "2.0.0", wantType: "VersionTooNew"},
+		{version: "2.0.0-rc1", wantType: "VersionTooNew"},
+		{version: "unknown", wantType: "VersionParseError"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			errorType, err := app.checkBackendVersion(tt.version)
+			if errorType != tt.wantType || (err != nil) != (tt.wantType != "") {
+				t.Errorf("checkBackendVersion(%q) = %q, %v, want %q", tt.version, errorType, err, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestCheckBackendCompatibility(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake interpreter is a shell script")
+	}
+
+	tests := []struct {
+		name     string
+		version  string
+		wantType string // empty when processing may go ahead
+	}{
+		{name: "minimum", version: minSupportedBackendVersion},
+		{name: "maximum", version: maxSupportedBackendVersion},
+		{name: "prefixed release candidate", version: "v1.4.0-rc1"},
+		{name: "too old", version: "0.9.9", wantType: "BackendVersionMismatch"},
+		{name: "too new", version: "2.0.0", wantType: "BackendVersionMismatch"},
+		// A version that cannot be read is left for the run itself to report
+		{name: "unreadable", version: "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, "backend"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "backend", backendScriptName), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+			// Stands in for python running process_video.py --version
+			python := filepath.Join(dir, "python")
+			if err := os.WriteFile(python, []byte("#!/bin/sh\necho '"+tt.version+"'\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			app.workingDir = dir
+			app.settings.Interpreter = interpreterPython
+			app.settings.PythonPath = python
+
+			response := app.checkBackendCompatibility()
+			switch {
+			case tt.wantType == "" && response != nil:
+				t.Errorf("checkBackendCompatibility() = %+v, want nil", response)
+			case tt.wantType != "" && (response == nil || response.ErrorType != tt.wantType):
+				t.Errorf("checkBackendCompatibility() = %+v, want %s", response, tt.wantType)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchSettleDelay is how long a new file must go without writes before it is
+// treated as complete and enqueued
+const watchSettleDelay = 3 * time.Second
+
+// defaultWatchOutputTemplate writes outputs to a subfolder, which the
+// non-recursive watch does not see
+const defaultWatchOutputTemplate = "{dir}/processed/{name}.mp4"
+
+// FolderWatch is a directory whose new videos are processed automatically
+type FolderWatch struct {
+	ID     string `json:"id"`
+	Dir    string `json:"dir"`
+	Preset string `json:"preset"` // analysis preset; empty uses the defaults
+	// OutputTemplate builds the output path. {dir}, {name}, {ext} and {date}
+	// are replaced with the input's folder, base name without extension,
+	// extension and the current date (YYYYMMDD).
+	OutputTemplate string    `json:"output_template"`
+	Enabled        bool      `json:"enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// folderWatcher is the running state of an enabled FolderWatch
+type folderWatcher struct {
+	watcher *fsnotify.Watcher
+	mu      sync.Mutex
+	pending map[string]*time.Timer // files waiting for their writes to settle
+	done    chan struct{}
+}
+
+// watchFolders holds the registered watches and the watchers of enabled ones
+type watchFolders struct {
+	mu       sync.Mutex
+	loaded   bool
+	watches  []FolderWatch
+	watchers map[string]*folderWatcher // watch ID -> running watcher
+}
+
+// watchFoldersPath returns the file the watches are persisted to
+func watchFoldersPath() (string, error) {
+	dir, err := appDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watch_folders.json"), nil
+}
+
+// expandOutputTemplate returns the output path for inputPath
+func expandOutputTemplate(template, inputPath string) string {
+	dir, name := filepath.Split(inputPath)
+	ext := filepath.Ext(name)
+	replacer := strings.NewReplacer(
+		"{dir}", filepath.Clean(dir),
+		"{name}", strings.TrimSuffix(name, ext),
+		"{ext}", ext,
+		"{date}", time.Now().Format("20060102"),
+	)
+	return filepath.Clean(replacer.Replace(template))
+}
+
+// validateWatch checks a watch before it is stored
+func validateWatch(watch FolderWatch) error {
+	info, err := os.Stat(watch.Dir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("watch folder is not a directory: %s", watch.Dir)
+	}
+	if !strings.Contains(watch.OutputTemplate, "{name}") {
+		return fmt.Errorf("output template must contain {name}, got %q", watch.OutputTemplate)
+	}
+	// Outputs written into the watched folder would be picked up and processed again
+	sample := expandOutputTemplate(watch.OutputTemplate, filepath.Join(watch.Dir, "sample.mp4"))
+	if !filepath.IsAbs(sample) {
+		return fmt.Errorf("output template must produce an absolute path, got %s", sample)
+	}
+	if filepath.Dir(sample) == filepath.Clean(watch.Dir) {
+		return fmt.Errorf("output template must not write into the watched folder itself")
+	}
+	if !isVideoFile(sample) {
+		return fmt.Errorf("output template must end in a video extension such as .mp4")
+	}
+	return nil
+}
+
+// folderWatchState returns the watch registry, loading the persisted watches on first use
+func (a *App) folderWatchState() *watchFolders {
+	a.mu.Lock()
+	if a.watches == nil {
+		a.watches = &watchFolders{watchers: make(map[string]*folderWatcher)}
+	}
+	state := a.watches
+	a.mu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if !state.loaded {
+		state.loaded = true
+		if path, err := watchFoldersPath(); err == nil {
+			if data, err := os.ReadFile(path); err == nil {
+				if err := json.Unmarshal(data, &state.watches); err != nil {
+					a.logger.Warn("ignoring unreadable watch folders", map[string]interface{}{"path": path, "error": err.Error()})
+				}
+			}
+		}
+	}
+	return state
+}
+
+// saveLocked persists the watches; state.mu must be held
+func (w *watchFolders) saveLocked() error {
+	path, err := watchFoldersPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(w.watches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watch folders: %v", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save watch folders: %v", err)
+	}
+	return nil
+}
+
+// restoreWatchFolders starts the watches that were enabled in the previous session
+func (a *App) restoreWatchFolders() {
+	state := a.folderWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for _, watch := range state.watches {
+		if !watch.Enabled {
+			continue
+		}
+		if err := a.startFolderWatchLocked(state, watch); err != nil {
+			a.logger.Warn("failed to restore watch folder", map[string]interface{}{"dir": watch.Dir, "error": err.Error()})
+		}
+	}
+}
+
+// AddWatchFolder registers a folder whose new videos are enqueued with the
+// given preset. An empty outputTemplate uses "{dir}/processed/{name}.mp4".
+// The watch starts enabled.
+func (a *App) AddWatchFolder(dir, preset, outputTemplate string) (FolderWatch, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return FolderWatch{}, fmt.Errorf("invalid watch folder %s: %v", dir, err)
+	}
+	if outputTemplate == "" {
+		outputTemplate = defaultWatchOutputTemplate
+	}
+	if preset != "" {
+		if _, err := a.LoadPreset(preset); err != nil {
+			return FolderWatch{}, err
+		}
+	}
+	watch := FolderWatch{
+		ID:             generateID(),
+		Dir:            absDir,
+		Preset:         preset,
+		OutputTemplate: outputTemplate,
+		Enabled:        true,
+		CreatedAt:      time.Now(),
+	}
+	if err := validateWatch(watch); err != nil {
+		return FolderWatch{}, err
+	}
+
+	state := a.folderWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	for _, existing := range state.watches {
+		if existing.Dir == absDir {
+			return FolderWatch{}, fmt.Errorf("folder is already watched: %s", absDir)
+		}
+	}
+	if err := a.startFolderWatchLocked(state, watch); err != nil {
+		return FolderWatch{}, err
+	}
+	state.watches = append(state.watches, watch)
+	if err := state.saveLocked(); err != nil {
+		return watch, err
+	}
+
+	a.logger.Info("watch folder added", map[string]interface{}{"watch_id": watch.ID, "dir": absDir, "preset": preset})
+	return watch, nil
+}
+
+// ListWatchFolders returns the registered watch folders
+func (a *App) ListWatchFolders() []FolderWatch {
+	state := a.folderWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	watches := append([]FolderWatch{}, state.watches...)
+	sort.Slice(watches, func(i, j int) bool { return watches[i].CreatedAt.Before(watches[j].CreatedAt) })
+	return watches
+}
+
+// SetWatchFolderEnabled starts or stops a watch without removing it
+func (a *App) SetWatchFolderEnabled(id string, enabled bool) error {
+	state := a.folderWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for i := range state.watches {
+		if state.watches[i].ID != id {
+			continue
+		}
+		if enabled && state.watchers[id] == nil {
+			if err := a.startFolderWatchLocked(state, state.watches[i]); err != nil {
+				return err
+			}
+		}
+		if !enabled {
+			stopFolderWatchLocked(state, id)
+		}
+		state.watches[i].Enabled = enabled
+		return state.saveLocked()
+	}
+	return fmt.Errorf("no watch folder with ID %s", id)
+}
+
+// RemoveWatchFolder stops and forgets a watch
+func (a *App) RemoveWatchFolder(id string) error {
+	state := a.folderWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for i := range state.watches {
+		if state.watches[i].ID == id {
+			stopFolderWatchLocked(state, id)
+			state.watches = append(state.watches[:i], state.watches[i+1:]...)
+			return state.saveLocked()
+		}
+	}
+	return fmt.Errorf("no watch folder with ID %s", id)
+}
+
+// startFolderWatchLocked begins watching a folder; state.mu must be held
+func (a *App) startFolderWatchLocked(state *watchFolders, watch FolderWatch) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create folder watcher: %v", err)
+	}
+	if err := watcher.Add(watch.Dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %v", watch.Dir, err)
+	}
+
+	running := &folderWatcher{
+		watcher: watcher,
+		pending: make(map[string]*time.Timer),
+		done:    make(chan struct{}),
+	}
+	state.watchers[watch.ID] = running
+	go a.watchFolderEvents(watch, running)
+	return nil
+}
+
+// stopFolderWatchLocked closes a running watch; state.mu must be held
+func stopFolderWatchLocked(state *watchFolders, id string) {
+	running, ok := state.watchers[id]
+	if !ok {
+		return
+	}
+	delete(state.watchers, id)
+	close(running.done)
+	running.watcher.Close()
+
+	running.mu.Lock()
+	for _, timer := range running.pending {
+		timer.Stop()
+	}
+	running.mu.Unlock()
+}
+
+// stopAll closes every running watch, leaving their enabled state untouched
+func (w *watchFolders) stopAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id := range w.watchers {
+		stopFolderWatchLocked(w, id)
+	}
+}
+
+// watchFolderEvents schedules new and growing video files until the watch is stopped.
+// Every write restarts the file's settle timer.
+func (a *App) watchFolderEvents(watch FolderWatch, running *folderWatcher) {
+	for {
+		select {
+		case event, ok := <-running.watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			// Hidden files include the backend's partial outputs
+			if strings.HasPrefix(filepath.Base(event.Name), ".") || !isVideoFile(event.Name) {
+				continue
+			}
+
+			path := event.Name
+			running.mu.Lock()
+			if timer, ok := running.pending[path]; ok {
+				timer.Reset(watchSettleDelay)
+			} else {
+				running.pending[path] = time.AfterFunc(watchSettleDelay, func() {
+					running.mu.Lock()
+					delete(running.pending, path)
+					running.mu.Unlock()
+
+					select {
+					case <-running.done:
+						return
+					default:
+					}
+					a.enqueueWatchedFile(watch, path)
+				})
+			}
+			running.mu.Unlock()
+		case err, ok := <-running.watcher.Errors:
+			if !ok {
+				return
+			}
+			a.logger.Warn("watch folder error", map[string]interface{}{"watch_id": watch.ID, "error": err.Error()})
+		}
+	}
+}
+
+// enqueueWatchedFile validates a settled file and adds it to the batch queue.
+// Existing outputs are skipped, so a file is not processed twice.
+func (a *App) enqueueWatchedFile(watch FolderWatch, path string) {
+	if err := checkReadableFile(path); err != nil {
+		a.logger.Warn("skipping watched file", map[string]interface{}{"watch_id": watch.ID, "path": path, "error": err.Error()})
+		return
+	}
+
+	request := ProcessVideoRequest{
+		InputPath:       path,
+		OutputPath:      expandOutputTemplate(watch.OutputTemplate, path),
+		Config:          "{}",
+		OverwritePolicy: overwritePolicySkip,
+	}
+	if watch.Preset != "" {
+		config, err := a.LoadPreset(watch.Preset)
+		if err != nil {
+			a.logger.Warn("watch folder preset unavailable", map[string]interface{}{"watch_id": watch.ID, "preset": watch.Preset, "error": err.Error()})
+			return
+		}
+		request.AnalysisConfig = &config
+	}
+
+	jobIDs := a.jobQueue().Enqueue([]ProcessVideoRequest{request})
+	a.logger.Info("watched file enqueued", map[string]interface{}{"watch_id": watch.ID, "path": path, "job_id": jobIDs[0]})
+	a.emit("watch:enqueued", map[string]interface{}{
+		"watch_id":    watch.ID,
+		"input_path":  path,
+		"output_path": request.OutputPath,
+		"job_id":      jobIDs[0],
+	})
+}
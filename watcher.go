@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// outputWatches tracks watched output files. One fsnotify watcher serves all
+// of them, since each watcher holds an inotify instance and those are limited
+// per user on Linux.
+type outputWatches struct {
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher // created on first use
+	paths   map[string]string // watcher ID -> path
+	ids     map[string]string // path -> watcher ID
+}
+
+// outputWatchState returns the app's output watches, creating them on first use
+func (a *App) outputWatchState() *outputWatches {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.outputWatches == nil {
+		a.outputWatches = &outputWatches{paths: make(map[string]string), ids: make(map[string]string)}
+	}
+	return a.outputWatches
+}
+
+// WatchOutputFile watches an output video and emits "output:deleted" or
+// "output:moved" when another process removes or renames it. It returns an
+// ID that can be passed to UnwatchOutputFile; watching a path that is
+// already watched returns the existing ID.
+func (a *App) WatchOutputFile(videoPath string) (string, error) {
+	absPath, err := filepath.Abs(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid output path %s: %v", videoPath, err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return "", fmt.Errorf("cannot access output file: %s. Error: %v", absPath, err)
+	}
+
+	state := a.outputWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if watcherID, ok := state.ids[absPath]; ok {
+		return watcherID, nil
+	}
+	if state.watcher == nil {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return "", fmt.Errorf("failed to create file watcher: %v", err)
+		}
+		state.watcher = watcher
+		go a.watchOutputEvents(state, watcher)
+	}
+	if err := state.watcher.Add(absPath); err != nil {
+		return "", fmt.Errorf("failed to watch %s: %v", absPath, err)
+	}
+
+	watcherID := generateID()
+	state.paths[watcherID] = absPath
+	state.ids[absPath] = watcherID
+	return watcherID, nil
+}
+
+// UnwatchOutputFile stops a watch created by WatchOutputFile
+func (a *App) UnwatchOutputFile(watcherID string) error {
+	state := a.outputWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	path, ok := state.paths[watcherID]
+	if !ok {
+		return fmt.Errorf("no output watcher with ID %s", watcherID)
+	}
+	state.removeLocked(watcherID, path)
+	return nil
+}
+
+// unwatchOutputPath stops watching path if it is watched. Call it before
+// replacing or deleting a watched file on purpose, so the change is not
+// reported as an outside deletion.
+func (a *App) unwatchOutputPath(path string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	state := a.outputWatchState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if watcherID, ok := state.ids[absPath]; ok {
+		state.removeLocked(watcherID, absPath)
+	}
+}
+
+// removeLocked forgets a watch; state.mu must be held. The file may already
+// be gone, in which case the watcher has dropped it itself.
+func (state *outputWatches) removeLocked(watcherID, path string) {
+	delete(state.paths, watcherID)
+	delete(state.ids, path)
+	if state.watcher != nil {
+		_ = state.watcher.Remove(path)
+	}
+}
+
+// close stops the shared watcher
+func (state *outputWatches) close() {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.watcher != nil {
+		state.watcher.Close()
+		state.watcher = nil
+	}
+	state.paths = make(map[string]string)
+	state.ids = make(map[string]string)
+}
+
+// watchOutputEvents forwards removal and rename events until the watcher is
+// closed. A watch is released once its file is gone, since the path no longer
+// refers to it.
+func (a *App) watchOutputEvents(state *outputWatches, watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			var eventName string
+			switch {
+			case event.Has(fsnotify.Remove):
+				eventName = "output:deleted"
+			case event.Has(fsnotify.Rename):
+				eventName = "output:moved"
+			default:
+				continue
+			}
+
+			path := filepath.Clean(event.Name)
+			state.mu.Lock()
+			watcherID, ok := state.ids[path]
+			if ok {
+				state.removeLocked(watcherID, path)
+			}
+			state.mu.Unlock()
+			// Events may still arrive for paths unwatched in the meantime
+			if !ok {
+				continue
+			}
+
+			a.emit(eventName, map[string]interface{}{
+				"watcher_id": watcherID,
+				"path":       path,
+			})
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}